@@ -2,34 +2,33 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"feints/internal/botserver"
 
 	"log/slog"
-	"os"
 	"time"
 
 	"github.com/lmittmann/tint"
 )
 
 func main() {
-	// Ejecutar botserver en una goroutine
-	
 	log := loggerSetup()
-	go func() {
 
-		if err := botserver.Run(log); err != nil {
-			log.Error("Bot server failed: %s", err)
-			return
-		}
-	}()
+	// SIGINT/SIGTERM cancelan el contexto y Run apaga el bot ordenadamente
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Mantener el main vivo
-	select {}
+	if err := botserver.Run(ctx, log); err != nil {
+		log.Error("Bot server failed", "err", err)
+		stop()
+		os.Exit(1)
+	}
 }
 
-
-
 func loggerSetup() *slog.Logger {
 	w := os.Stderr
 
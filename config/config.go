@@ -1 +1,188 @@
+// Package config centraliza la configuración global del bot.
+// Los valores se leen de variables de entorno y tienen valores por defecto razonables.
 package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCrossfade es el fundido máximo permitido entre canciones.
+const MaxCrossfade = 12 * time.Second
+
+// Config agrupa los ajustes globales del bot.
+type Config struct {
+	// AutoplayDiscoveryRatio es la proporción (0-1) de canciones nuevas
+	// (relacionadas) frente a canciones conocidas de la biblioteca local.
+	AutoplayDiscoveryRatio float64
+	// AutoplayHistorySize es cuántas reproducciones recientes se evitan repetir.
+	AutoplayHistorySize int
+	// Crossfade es el fundido entre canciones (0 = empalme sin hueco).
+	Crossfade time.Duration
+
+	// Valores por defecto de los ajustes por guild
+	Language        string
+	MaxSongDuration time.Duration
+	MaxQueueLength  int
+	DefaultVolume   int
+	DJRole          string
+	VoteSkipPercent int
+	MaxUserSongs    int           // 0 = sin límite
+	MaxUserDuration time.Duration // 0 = sin límite
+	QueueStrategy   string
+	AloneTimeout    time.Duration // 0 = nunca
+	IdleTimeout     time.Duration // 0 = nunca
+
+	// ShutdownTimeout es el margen para guardar las colas y desconectar
+	// al apagar.
+	ShutdownTimeout time.Duration
+	// QueueMaxAge es cuánto vale una cola guardada al apagar: pasado ese
+	// tiempo ya no se restaura. 0 = nunca caduca.
+	QueueMaxAge time.Duration
+
+	// ShardCount es el total de shards del bot y ShardIDs los que atiende
+	// este proceso (por defecto, todos).
+	ShardCount int
+	ShardIDs   []int
+	// GatewayAPI sustituye la API de Discord al descubrir el gateway (p. ej.
+	// un gateway falso local para pruebas). Vacío = Discord.
+	GatewayAPI string
+
+	// DevGuildID registra los comandos sólo en ese guild, donde se
+	// actualizan al momento (para desarrollo). Vacío = comandos globales.
+	DevGuildID string
+
+	// MetricsAddr es la dirección donde se sirven las métricas (vacía = no
+	// se sirven).
+	MetricsAddr string
+}
+
+// Global es la configuración cargada al iniciar el proceso. Err recoge los
+// valores inválidos que no se pueden suplir con el de por defecto; con él,
+// el bot no arranca.
+var Global, Err = Load()
+
+// Load lee la configuración desde el entorno. Aun con error devuelve una
+// configuración utilizable, con los valores por defecto en lo inválido.
+func Load() (*Config, error) {
+	// Un error en los shards haría que este proceso atendiera shards de
+	// otro (respuestas duplicadas), así que no se corrige en silencio
+	shardCount, countErr := parseShardCount(os.Getenv("SHARD_COUNT"))
+	shardIDs, idsErr := parseShardIDs(os.Getenv("SHARD_IDS"), shardCount)
+	return &Config{
+		AutoplayDiscoveryRatio: clamp(envFloat("AUTOPLAY_DISCOVERY_RATIO", 0.3), 0, 1),
+		AutoplayHistorySize:    envInt("AUTOPLAY_HISTORY_SIZE", 20),
+		Crossfade:              envSeconds("CROSSFADE_SECONDS", 0, MaxCrossfade),
+		Language:               envString("BOT_LANGUAGE", "es"),
+		MaxSongDuration:        envDuration("MAX_SONG_DURATION", 15*time.Minute),
+		MaxQueueLength:         envInt("MAX_QUEUE_LENGTH", 50),
+		DefaultVolume:          envInt("DEFAULT_VOLUME", 100),
+		DJRole:                 envString("DJ_ROLE", ""),
+		VoteSkipPercent:        envInt("VOTE_SKIP_PERCENT", 50),
+		MaxUserSongs:           envInt("MAX_USER_SONGS", 0),
+		MaxUserDuration:        envDuration("MAX_USER_DURATION", 0),
+		QueueStrategy:          envString("QUEUE_STRATEGY", "fifo"),
+		AloneTimeout:           envDuration("ALONE_TIMEOUT", 5*time.Minute),
+		IdleTimeout:            envDuration("IDLE_TIMEOUT", 5*time.Minute),
+		ShutdownTimeout:        envDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		QueueMaxAge:            envDuration("QUEUE_MAX_AGE", time.Hour),
+		MetricsAddr:            envString("METRICS_ADDR", ""),
+		ShardCount:             shardCount,
+		ShardIDs:               shardIDs,
+		GatewayAPI:             envString("DISCORD_GATEWAY_API", ""),
+		DevGuildID:             envString("DEV_GUILD_ID", ""),
+	}, errors.Join(countErr, idsErr)
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+func envFloat(key string, def float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return def
+}
+
+// envSeconds lee una duración en segundos (admite decimales) limitada a [0, maxDur].
+func envSeconds(key string, def float64, maxDur time.Duration) time.Duration {
+	secs := clamp(envFloat(key, def), 0, maxDur.Seconds())
+	return time.Duration(secs * float64(time.Second))
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return min(max(v, lo), hi)
+}
+
+// parseShardCount lee SHARD_COUNT: vacío es 1 shard.
+func parseShardCount(spec string) (int, error) {
+	if spec == "" {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(spec)
+	if err != nil || count < 1 {
+		return 1, fmt.Errorf("SHARD_COUNT inválido: %q", spec)
+	}
+	return count, nil
+}
+
+// parseShardIDs interpreta una lista de shards como "0-3" o "0,2,5-7"; sin
+// lista se atienden todos. Un id fuera de [0, count) o una parte mal escrita
+// es un error.
+func parseShardIDs(spec string, count int) ([]int, error) {
+	var ids []int
+	if strings.TrimSpace(spec) == "" {
+		for id := range count {
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(lo)
+		to := from
+		if err == nil && isRange {
+			to, err = strconv.Atoi(hi)
+		}
+		if err != nil || from > to {
+			return nil, fmt.Errorf("SHARD_IDS inválido: %q", part)
+		}
+		if from < 0 || to >= count {
+			return nil, fmt.Errorf("SHARD_IDS: %q fuera de 0-%d (SHARD_COUNT=%d)", part, count-1, count)
+		}
+		for id := from; id <= to; id++ {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseShardIDs(t *testing.T) {
+	tests := []struct {
+		spec    string
+		count   int
+		want    []int
+		wantErr bool
+	}{
+		{spec: "", count: 4, want: []int{0, 1, 2, 3}},
+		{spec: "  ", count: 2, want: []int{0, 1}},
+		{spec: "0-3", count: 8, want: []int{0, 1, 2, 3}},
+		{spec: "0,2,5-7", count: 8, want: []int{0, 2, 5, 6, 7}},
+		{spec: " 6 , 1-2 ", count: 8, want: []int{1, 2, 6}},
+		{spec: "1,1,0-1", count: 2, want: []int{0, 1}},
+		{spec: "3", count: 4, want: []int{3}},
+		{spec: "4", count: 4, wantErr: true},
+		{spec: "2-5", count: 4, wantErr: true},
+		{spec: "-1", count: 4, wantErr: true},
+		{spec: "3-1", count: 4, wantErr: true},
+		{spec: "0,x", count: 4, wantErr: true},
+		{spec: "0,", count: 4, wantErr: true},
+		{spec: "1-", count: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseShardIDs(tt.spec, tt.count)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseShardIDs(%q, %d) err = %v, wantErr %v", tt.spec, tt.count, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !slices.Equal(got, tt.want) {
+			t.Errorf("parseShardIDs(%q, %d) = %v, want %v", tt.spec, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestParseShardCount(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    int
+		wantErr bool
+	}{
+		{spec: "", want: 1},
+		{spec: "1", want: 1},
+		{spec: "16", want: 16},
+		{spec: "0", wantErr: true},
+		{spec: "-2", wantErr: true},
+		{spec: "dos", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseShardCount(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseShardCount(%q) err = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseShardCount(%q) = %d, want %d", tt.spec, got, tt.want)
+		}
+	}
+}
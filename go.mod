@@ -4,15 +4,15 @@ go 1.25
 
 require (
 	github.com/bogem/id3v2 v1.2.0
-	github.com/bwmarrin/dgvoice v0.0.0-20210225172318-caaac756e02e
 	github.com/bwmarrin/discordgo v0.29.0
+	github.com/gorilla/websocket v1.4.2
+	github.com/gorilla/websocket v1.4.2
 	github.com/lmittmann/tint v1.1.2
+	layeh.com/gopus v0.0.0-20210501142526-1ee02d434e32
 )
 
 require (
-	github.com/gorilla/websocket v1.4.2 // indirect
 	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b // indirect
 	golang.org/x/sys v0.0.0-20201119102817-f84b799fce68 // indirect
 	golang.org/x/text v0.3.3 // indirect
-	layeh.com/gopus v0.0.0-20210501142526-1ee02d434e32 // indirect
 )
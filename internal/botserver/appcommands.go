@@ -0,0 +1,180 @@
+package botserver
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/commands"
+)
+
+// syncCommands deja registrados exactamente los comandos de commands.All:
+// globales o, con devGuildID, sólo en ese guild, donde se actualizan al
+// momento (los globales tardan en propagarse). En ese caso vacía los
+// globales, que si no aparecerían dos veces en el guild de pruebas.
+func syncCommands(s *discordgo.Session, devGuildID string, log *slog.Logger) error {
+	var desired []*discordgo.ApplicationCommand
+	for _, cmd := range commands.All {
+		def := cmd.Definition()
+		localizeCommand(def)
+		desired = append(desired, def)
+	}
+	if devGuildID == "" {
+		return syncScope(s, "", desired, log)
+	}
+	if err := syncScope(s, devGuildID, desired, log); err != nil {
+		return err
+	}
+	return syncScope(s, "", nil, log)
+}
+
+// syncScope registra desired en guildID (vacío = globales). Sólo escribe,
+// con un único BulkOverwrite, si algo ha cambiado; así también desaparecen
+// los comandos que ya no existen.
+func syncScope(s *discordgo.Session, guildID string, desired []*discordgo.ApplicationCommand, log *slog.Logger) error {
+	appID := s.State.User.ID
+	log = log.With("guildID", guildID)
+
+	registered, err := s.ApplicationCommands(appID, guildID)
+	if err != nil {
+		return fmt.Errorf("no se pudieron leer los comandos registrados: %w", err)
+	}
+
+	added, changed, removed := diffCommands(registered, desired)
+	if len(added)+len(changed)+len(removed) == 0 {
+		log.Info("Comandos al día", "count", len(desired))
+		return nil
+	}
+	log.Info("Actualizando comandos", "added", added, "changed", changed, "removed", removed)
+	// nil se enviaría como null; para borrarlos todos hace falta una lista vacía
+	if desired == nil {
+		desired = []*discordgo.ApplicationCommand{}
+	}
+	if _, err := s.ApplicationCommandBulkOverwrite(appID, guildID, desired); err != nil {
+		return fmt.Errorf("no se pudieron registrar los comandos: %w", err)
+	}
+	return nil
+}
+
+// diffCommands compara los comandos registrados con los deseados por nombre
+// y devuelve los nombres de los nuevos, los modificados y los que sobran.
+func diffCommands(registered, desired []*discordgo.ApplicationCommand) (added, changed, removed []string) {
+	current := make(map[string]commandShape, len(registered))
+	for _, cmd := range registered {
+		current[cmd.Name] = shapeOf(cmd)
+	}
+	for _, cmd := range desired {
+		shape, ok := current[cmd.Name]
+		switch {
+		case !ok:
+			added = append(added, cmd.Name)
+		case !reflect.DeepEqual(shape, shapeOf(cmd)):
+			changed = append(changed, cmd.Name)
+		}
+		delete(current, cmd.Name)
+	}
+	removed = slices.Sorted(maps.Keys(current))
+	return added, changed, removed
+}
+
+// commandShape es lo que define un comando a efectos de saber si ha
+// cambiado: se ignoran ids, versiones y los valores por defecto que rellena
+// Discord al devolverlo.
+type commandShape struct {
+	Type                     discordgo.ApplicationCommandType
+	Name, Description        string
+	NameLocalizations        map[discordgo.Locale]string
+	DescriptionLocalizations map[discordgo.Locale]string
+	Permissions              string
+	Options                  []optionShape
+}
+
+type optionShape struct {
+	Type                     discordgo.ApplicationCommandOptionType
+	Name, Description        string
+	NameLocalizations        map[discordgo.Locale]string
+	DescriptionLocalizations map[discordgo.Locale]string
+	Required, Autocomplete   bool
+	Choices                  []choiceShape
+	Options                  []optionShape
+	ChannelTypes             []discordgo.ChannelType
+	MinValue                 string // "" = sin mínimo (0 es un mínimo válido)
+	MaxValue                 float64
+	MinLength, MaxLength     int
+}
+
+type choiceShape struct {
+	Name              string
+	NameLocalizations map[discordgo.Locale]string
+	Value             string
+}
+
+func shapeOf(cmd *discordgo.ApplicationCommand) commandShape {
+	shape := commandShape{
+		Type:        cmd.Type,
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		Options:     optionShapes(cmd.Options),
+	}
+	if shape.Type == 0 {
+		shape.Type = discordgo.ChatApplicationCommand
+	}
+	if cmd.NameLocalizations != nil {
+		shape.NameLocalizations = nonEmpty(*cmd.NameLocalizations)
+	}
+	if cmd.DescriptionLocalizations != nil {
+		shape.DescriptionLocalizations = nonEmpty(*cmd.DescriptionLocalizations)
+	}
+	if cmd.DefaultMemberPermissions != nil {
+		shape.Permissions = fmt.Sprint(*cmd.DefaultMemberPermissions)
+	}
+	return shape
+}
+
+func optionShapes(opts []*discordgo.ApplicationCommandOption) []optionShape {
+	var shapes []optionShape
+	for _, opt := range opts {
+		shape := optionShape{
+			Type:                     opt.Type,
+			Name:                     opt.Name,
+			Description:              opt.Description,
+			NameLocalizations:        nonEmpty(opt.NameLocalizations),
+			DescriptionLocalizations: nonEmpty(opt.DescriptionLocalizations),
+			Required:                 opt.Required,
+			Autocomplete:             opt.Autocomplete,
+			Options:                  optionShapes(opt.Options),
+			MaxValue:                 opt.MaxValue,
+			MaxLength:                opt.MaxLength,
+		}
+		if len(opt.ChannelTypes) > 0 {
+			shape.ChannelTypes = opt.ChannelTypes
+		}
+		if opt.MinValue != nil {
+			shape.MinValue = fmt.Sprint(*opt.MinValue)
+		}
+		if opt.MinLength != nil {
+			shape.MinLength = *opt.MinLength
+		}
+		for _, choice := range opt.Choices {
+			shape.Choices = append(shape.Choices, choiceShape{
+				Name:              choice.Name,
+				NameLocalizations: nonEmpty(choice.NameLocalizations),
+				Value:             fmt.Sprint(choice.Value),
+			})
+		}
+		shapes = append(shapes, shape)
+	}
+	return shapes
+}
+
+// nonEmpty trata igual un mapa vacío que uno sin definir.
+func nonEmpty(m map[discordgo.Locale]string) map[discordgo.Locale]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,190 @@
+package botserver
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestDiffCommands(t *testing.T) {
+	play := func() *discordgo.ApplicationCommand {
+		return &discordgo.ApplicationCommand{
+			Name:        "play",
+			Description: "Reproduce una canción",
+			NameLocalizations: &map[discordgo.Locale]string{
+				discordgo.EnglishUS: "play",
+			},
+			Options: []*discordgo.ApplicationCommandOption{{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "query",
+				Description:  "Canción o URL",
+				Required:     true,
+				Autocomplete: true,
+			}},
+		}
+	}
+	cmd := func(name, description string) *discordgo.ApplicationCommand {
+		return &discordgo.ApplicationCommand{Name: name, Description: description}
+	}
+	// registered devuelve play tal como lo manda Discord: con ids, versión y
+	// los valores por defecto rellenos
+	registered := func() *discordgo.ApplicationCommand {
+		c := play()
+		c.ID, c.ApplicationID, c.Version = "1", "2", "3"
+		c.Type = discordgo.ChatApplicationCommand
+		c.DescriptionLocalizations = &map[discordgo.Locale]string{}
+		c.Options[0].NameLocalizations = map[discordgo.Locale]string{}
+		return c
+	}
+	// limits es un comando con opciones acotadas; change modifica las
+	// opciones del deseado
+	limits := func(change func(opts []*discordgo.ApplicationCommandOption)) *discordgo.ApplicationCommand {
+		zero, one := 0.0, 1
+		opts := []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "volume", MinValue: &zero, MaxValue: 200},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "title", MinLength: &one, MaxLength: 100},
+			{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel",
+				ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText}},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "strategy", Choices: []*discordgo.ApplicationCommandOptionChoice{{
+				Name: "round_robin", Value: "round_robin",
+				NameLocalizations: map[discordgo.Locale]string{discordgo.SpanishES: "por turnos"},
+			}}},
+		}
+		if change != nil {
+			change(opts)
+		}
+		return &discordgo.ApplicationCommand{Name: "limits", Description: "Límites", Options: opts}
+	}
+	limitsChanged := func(change func(opts []*discordgo.ApplicationCommandOption)) []*discordgo.ApplicationCommand {
+		return []*discordgo.ApplicationCommand{limits(change)}
+	}
+	unchanged := []*discordgo.ApplicationCommand{limits(nil)}
+
+	tests := []struct {
+		name                    string
+		registered, desired     []*discordgo.ApplicationCommand
+		added, changed, removed []string
+	}{
+		{
+			name:    "nothing registered",
+			desired: []*discordgo.ApplicationCommand{play(), cmd("stop", "Para")},
+			added:   []string{"play", "stop"},
+		},
+		{
+			name:       "identical",
+			registered: []*discordgo.ApplicationCommand{play(), cmd("stop", "Para")},
+			desired:    []*discordgo.ApplicationCommand{play(), cmd("stop", "Para")},
+		},
+		{
+			name:       "defaults filled in by Discord",
+			registered: []*discordgo.ApplicationCommand{registered()},
+			desired:    []*discordgo.ApplicationCommand{play()},
+		},
+		{
+			name:       "description changed",
+			registered: []*discordgo.ApplicationCommand{play(), cmd("stop", "Para")},
+			desired:    []*discordgo.ApplicationCommand{play(), cmd("stop", "Para la música")},
+			changed:    []string{"stop"},
+		},
+		{
+			name:       "option changed",
+			registered: []*discordgo.ApplicationCommand{registered()},
+			desired: []*discordgo.ApplicationCommand{func() *discordgo.ApplicationCommand {
+				c := play()
+				c.Options[0].Required = false
+				return c
+			}()},
+			changed: []string{"play"},
+		},
+		{
+			name:       "localization changed",
+			registered: []*discordgo.ApplicationCommand{registered()},
+			desired: []*discordgo.ApplicationCommand{func() *discordgo.ApplicationCommand {
+				c := play()
+				c.DescriptionLocalizations = &map[discordgo.Locale]string{discordgo.EnglishUS: "Play a song"}
+				return c
+			}()},
+			changed: []string{"play"},
+		},
+		{
+			name:       "stale commands removed, sorted",
+			registered: []*discordgo.ApplicationCommand{cmd("zap", "z"), play(), cmd("old", "o")},
+			desired:    []*discordgo.ApplicationCommand{play(), cmd("new", "n")},
+			added:      []string{"new"},
+			removed:    []string{"old", "zap"},
+		},
+		{
+			name:       "limits unchanged",
+			registered: unchanged,
+			desired:    []*discordgo.ApplicationCommand{limits(nil)},
+		},
+		{
+			name:       "channel types returned empty",
+			registered: limitsChanged(func(opts []*discordgo.ApplicationCommandOption) { opts[1].ChannelTypes = []discordgo.ChannelType{} }),
+			desired:    unchanged,
+		},
+		{
+			name:       "min value changed",
+			registered: unchanged,
+			desired:    limitsChanged(func(opts []*discordgo.ApplicationCommandOption) { v := 1.0; opts[0].MinValue = &v }),
+			changed:    []string{"limits"},
+		},
+		{
+			// 0 es un mínimo, no la falta de él
+			name:       "min value removed",
+			registered: unchanged,
+			desired:    limitsChanged(func(opts []*discordgo.ApplicationCommandOption) { opts[0].MinValue = nil }),
+			changed:    []string{"limits"},
+		},
+		{
+			name:       "max value changed",
+			registered: unchanged,
+			desired:    limitsChanged(func(opts []*discordgo.ApplicationCommandOption) { opts[0].MaxValue = 100 }),
+			changed:    []string{"limits"},
+		},
+		{
+			name:       "min length changed",
+			registered: unchanged,
+			desired:    limitsChanged(func(opts []*discordgo.ApplicationCommandOption) { n := 3; opts[1].MinLength = &n }),
+			changed:    []string{"limits"},
+		},
+		{
+			name:       "max length changed",
+			registered: unchanged,
+			desired:    limitsChanged(func(opts []*discordgo.ApplicationCommandOption) { opts[1].MaxLength = 50 }),
+			changed:    []string{"limits"},
+		},
+		{
+			name:       "channel types changed",
+			registered: unchanged,
+			desired: limitsChanged(func(opts []*discordgo.ApplicationCommandOption) {
+				opts[2].ChannelTypes = append(opts[2].ChannelTypes, discordgo.ChannelTypeGuildVoice)
+			}),
+			changed: []string{"limits"},
+		},
+		{
+			name:       "choice localization changed",
+			registered: unchanged,
+			desired: limitsChanged(func(opts []*discordgo.ApplicationCommandOption) {
+				opts[3].Choices[0].NameLocalizations = map[discordgo.Locale]string{discordgo.SpanishES: "turnos"}
+			}),
+			changed: []string{"limits"},
+		},
+		{
+			// con DEV_GUILD_ID, los globales se vacían
+			name:       "clear all",
+			registered: []*discordgo.ApplicationCommand{play(), cmd("stop", "Para")},
+			removed:    []string{"play", "stop"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, changed, removed := diffCommands(tt.registered, tt.desired)
+			if !slices.Equal(added, tt.added) || !slices.Equal(changed, tt.changed) || !slices.Equal(removed, tt.removed) {
+				t.Errorf("diffCommands() = %v, %v, %v; want %v, %v, %v",
+					added, changed, removed, tt.added, tt.changed, tt.removed)
+			}
+		})
+	}
+}
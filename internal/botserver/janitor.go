@@ -0,0 +1,113 @@
+package botserver
+
+import (
+	"time"
+
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+// janitorInterval es cada cuánto se revisan los players.
+const janitorInterval = 15 * time.Second
+
+// presence recuerda, por guild, desde cuándo el bot está solo en su canal o
+// sin nada que reproducir, y si la pausa actual la puso el janitor.
+type presence struct {
+	aloneSince time.Time
+	idleSince  time.Time
+	autoPaused bool
+}
+
+// StartJanitor revisa los players hasta que se cierre stop y retira los que
+// llevan demasiado tiempo solos (alone_timeout) o sin nada que reproducir
+// (idle_timeout).
+func (bs *BotServer) StartJanitor(stop <-chan struct{}) {
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			bs.sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (bs *BotServer) sweep() {
+	now := time.Now()
+	for guildID, e := range bs.players.snapshot() {
+		// Por si se perdió algún evento de voz
+		bs.watchListeners(guildID, e)
+
+		dp := e.player
+		settings := infra.GlobalSettings.Get(guildID)
+		idle := dp.State() == "idle" && len(dp.ListQueue()) == 0
+		reason := ""
+		bs.players.withPresence(e, func(p *presence) {
+			switch {
+			case !idle:
+				p.idleSince = time.Time{}
+			case p.idleSince.IsZero():
+				p.idleSince = now
+			}
+			if limit := settings.Duration(core.SettingAloneTimeout); limit > 0 && !p.aloneSince.IsZero() && now.Sub(p.aloneSince) >= limit {
+				reason = "alone"
+			}
+			if limit := settings.Duration(core.SettingIdleTimeout); limit > 0 && !p.idleSince.IsZero() && now.Sub(p.idleSince) >= limit {
+				reason = "idle"
+			}
+		})
+		if reason != "" {
+			bs.removePlayer(guildID, reason)
+		}
+	}
+	bs.limiter.prune()
+	bs.Log.Debug("Janitor", "players", metricPlayersActive.Value())
+}
+
+// watchListeners pausa el player cuando se queda sin oyentes y lo reanuda
+// cuando vuelve alguno, si fue el janitor quien lo pausó.
+func (bs *BotServer) watchListeners(guildID string, e *playerEntry) {
+	guild, err := bs.sessionFor(guildID).State.Guild(guildID)
+	if err != nil {
+		return
+	}
+	dp := e.player
+	alone := bs.listeners(guild, dp.VoiceChannel()) == 0
+
+	var pause, resume bool
+	bs.players.withPresence(e, func(p *presence) {
+		switch {
+		case alone && p.aloneSince.IsZero():
+			p.aloneSince = time.Now()
+			pause = dp.State() == "playing"
+			p.autoPaused = pause
+		case !alone && !p.aloneSince.IsZero():
+			p.aloneSince = time.Time{}
+			resume = p.autoPaused && dp.State() == "paused"
+			p.autoPaused = false
+		}
+	})
+
+	if pause {
+		bs.Log.Info("Sin oyentes: pausando", "guildID", guildID)
+		dp.Pause()
+	}
+	if resume {
+		bs.Log.Info("Vuelven los oyentes: reanudando", "guildID", guildID)
+		dp.Resume()
+	}
+}
+
+// removePlayer saca el player del registro y lo cierra junto con su mensaje
+// "now playing". El siguiente comando creará uno nuevo.
+func (bs *BotServer) removePlayer(guildID, reason string) {
+	e, ok := bs.players.remove(guildID)
+	if !ok {
+		return
+	}
+	bs.Log.Info("Retirando player", "guildID", guildID, "reason", reason)
+	e.player.Close()
+	e.nowPlaying.Close()
+}
@@ -0,0 +1,55 @@
+package botserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/config"
+	"feints/internal/core"
+	"feints/internal/i18n"
+)
+
+// descriptionArgs son los argumentos de las descripciones que los necesitan.
+var descriptionArgs = map[string][]any{
+	"cmd.filter.value": {strings.Join(core.EQPresets, ", ")},
+}
+
+// localizeCommand rellena el nombre y la descripción del comando, de sus
+// opciones y de sus valores elegibles desde el catálogo de i18n (claves
+// cmd.<comando>.<opción>...). Los textos base van en el idioma por defecto
+// del bot; los de cada idioma, en los mapas de localización de Discord.
+func localizeCommand(cmd *discordgo.ApplicationCommand) {
+	lang := i18n.Resolve("", "", nil, config.Global.Language)
+	key := "cmd." + cmd.Name
+	cmd.Description = i18n.T(lang, key)
+	cmd.DescriptionLocalizations = localizationsPtr(i18n.Localizations(key))
+	cmd.NameLocalizations = localizationsPtr(i18n.Localizations(key + ".name"))
+	localizeOptions(cmd.Options, key, lang)
+}
+
+func localizeOptions(opts []*discordgo.ApplicationCommandOption, prefix string, lang i18n.Lang) {
+	for _, opt := range opts {
+		key := prefix + "." + opt.Name
+		args := descriptionArgs[key]
+		opt.Description = i18n.T(lang, key, args...)
+		opt.DescriptionLocalizations = i18n.Localizations(key, args...)
+		opt.NameLocalizations = i18n.Localizations(key + ".name")
+		for _, choice := range opt.Choices {
+			choiceKey := key + "." + fmt.Sprint(choice.Value)
+			if i18n.Has(i18n.Fallback, choiceKey) {
+				choice.Name = i18n.T(lang, choiceKey)
+				choice.NameLocalizations = i18n.Localizations(choiceKey)
+			}
+		}
+		localizeOptions(opt.Options, key, lang)
+	}
+}
+
+func localizationsPtr(m map[discordgo.Locale]string) *map[discordgo.Locale]string {
+	if m == nil {
+		return nil
+	}
+	return &m
+}
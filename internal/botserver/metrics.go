@@ -0,0 +1,28 @@
+package botserver
+
+import (
+	"expvar"
+	"log/slog"
+	"net/http"
+)
+
+// Métricas publicadas con expvar; se sirven en /debug/vars si METRICS_ADDR
+// está definido.
+var (
+	metricPlayersActive  = expvar.NewInt("players_active")
+	metricPlayersCreated = expvar.NewInt("players_created")
+	metricPlayersClosed  = expvar.NewInt("players_closed")
+)
+
+// publishShardMetrics publica el estado de los shards de bs como "shards".
+func publishShardMetrics(bs *BotServer) {
+	expvar.Publish("shards", expvar.Func(func() any { return bs.shardStats() }))
+}
+
+// serveMetrics expone /debug/vars en addr.
+func serveMetrics(addr string, log *slog.Logger) {
+	log.Info("Métricas disponibles", "addr", addr, "path", "/debug/vars")
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		log.Error("Servidor de métricas detenido", "err", err)
+	}
+}
@@ -0,0 +1,284 @@
+package botserver
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/config"
+	"feints/internal/commands"
+	"feints/internal/core"
+	"feints/internal/i18n"
+	"feints/internal/infra"
+)
+
+// middleware envuelve la ejecución de un comando. Recibe el comando para
+// poder consultar su nombre, su política o si necesita voz.
+type middleware func(cmd commands.Command, next commands.Handler) commands.Handler
+
+// middlewares es la cadena por la que pasan todos los comandos y botones,
+// de fuera adentro. Los permisos van antes que withPlayer para que un
+// comando denegado no cree ni mueva el player.
+func (bs *BotServer) middlewares() []middleware {
+	return []middleware{bs.recovery, bs.logging, bs.guildOnly, bs.rateLimit, bs.permissions, bs.withPlayer}
+}
+
+// dispatch ejecuta exec (el comando o uno de sus botones) a través de los
+// middleware.
+func (bs *BotServer) dispatch(cmd commands.Command, exec commands.Handler, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	mws := bs.middlewares()
+	h := exec
+	for n := len(mws) - 1; n >= 0; n-- {
+		h = mws[n](cmd, h)
+	}
+	h(nil, s, i)
+}
+
+// logging registra cada ejecución y lo que tardó.
+func (bs *BotServer) logging(cmd commands.Command, next commands.Handler) commands.Handler {
+	return func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		start := time.Now()
+		next(dp, s, i)
+		bs.Log.Info("Comando ejecutado", "cmd", cmd.Definition().Name, "type", i.Type.String(),
+			"userID", interactionUserID(i), "guildID", i.GuildID, "duration", time.Since(start))
+	}
+}
+
+// recovery evita que un pánico en un comando tumbe el handler de discordgo.
+// Lo registra con un código de error y se lo da al usuario, para que pueda
+// buscarse en los logs.
+func (bs *BotServer) recovery(cmd commands.Command, next commands.Handler) commands.Handler {
+	return func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		defer func() {
+			if r := recover(); r != nil {
+				errorID := bs.logPanic(cmd.Definition().Name, r, i)
+				reportError(s, i, i18n.T(commands.Lang(i), "error.internal", errorID))
+			}
+		}()
+		next(dp, s, i)
+	}
+}
+
+// guildOnly rechaza las interacciones de fuera de un guild (DMs), que no
+// traen Member: todos los comandos trabajan sobre el guild.
+func (bs *BotServer) guildOnly(cmd commands.Command, next commands.Handler) commands.Handler {
+	return func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.GuildID == "" || i.Member == nil || i.Member.User == nil {
+			respondEphemeral(s, i, i18n.T(commands.Lang(i), "error.guild_only"))
+			return
+		}
+		next(dp, s, i)
+	}
+}
+
+// rateLimit rechaza a quien manda demasiados comandos seguidos.
+func (bs *BotServer) rateLimit(cmd commands.Command, next commands.Handler) commands.Handler {
+	return func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if wait := bs.limiter.allow(i.Member.User.ID); wait > 0 {
+			bs.Log.Info("Límite de comandos", "cmd", cmd.Definition().Name, "userID", i.Member.User.ID)
+			respondEphemeral(s, i, i18n.T(commands.Lang(i), "rate.limited", int(wait.Seconds())+1))
+			return
+		}
+		next(dp, s, i)
+	}
+}
+
+// withPlayer pasa el player del guild según el VoiceMode del comando. Los de
+// sólo lectura reciben el que haya, o nil, sin crear nada ni mirar la voz del
+// usuario. El resto exige que el usuario esté en un canal y crea el player si
+// no existe; los de control, además, que sea el canal del bot. Con
+// AttachesNowPlaying, el mensaje "now playing" pasa al canal del comando.
+func (bs *BotServer) withPlayer(cmd commands.Command, next commands.Handler) commands.Handler {
+	return func(_ core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		mode := cmd.VoiceMode()
+		if mode == commands.VoiceNone {
+			var dp core.Player
+			if e, ok := bs.players.get(i.GuildID); ok {
+				dp = e.player
+			}
+			next(dp, s, i)
+			return
+		}
+
+		guild, err := s.State.Guild(i.GuildID)
+		if err != nil {
+			bs.failInteraction(s, i, "No se pudo obtener guild", "err", err, "guildID", i.GuildID)
+			return
+		}
+		channelID := userVoiceChannel(guild, i.Member.User.ID)
+		if channelID == "" {
+			respondEphemeral(s, i, i18n.T(commands.Lang(i), "not_in_voice"))
+			return
+		}
+		dp, err := bs.GetOrCreatePlayer(i.GuildID, channelID)
+		if err != nil {
+			bs.failInteraction(s, i, "Error obteniendo player", "err", err, "guildID", i.GuildID)
+			return
+		}
+		if mode == commands.VoiceSame && dp.VoiceChannel() != channelID {
+			bs.Log.Info("Usuario en otro canal", "cmd", cmd.Definition().Name,
+				"userID", i.Member.User.ID, "guildID", i.GuildID)
+			respondEphemeral(s, i, i18n.T(commands.Lang(i), "voice.other_channel", dp.VoiceChannel()))
+			return
+		}
+		if cmd.RestoresQueue() {
+			// Lo que sonaba antes del último apagado vuelve a la cola, pero
+			// sólo cuando alguien pide música o llama al bot
+			if n, err := infra.RestoreQueue(i.GuildID, dp, config.Global.QueueMaxAge); err != nil {
+				bs.Log.Error("No se pudo restaurar la cola", "err", err, "guildID", i.GuildID)
+			} else if n > 0 {
+				bs.Log.Info("Cola restaurada", "guildID", i.GuildID, "songs", n)
+			}
+		}
+		if cmd.AttachesNowPlaying() {
+			if e, ok := bs.players.get(i.GuildID); ok {
+				e.nowPlaying.Attach(i.ChannelID)
+			}
+		}
+		next(dp, s, i)
+	}
+}
+
+// permissions aplica la política del comando. Si el usuario no puede, le
+// responde con el motivo o, en los comandos que se votan, registra su voto.
+// Sólo mira el player existente, y sólo si la política lo necesita: nunca
+// lo crea. El voto pasa antes por withPlayer, así que sólo votan quienes
+// podrían usar el comando por su canal de voz.
+func (bs *BotServer) permissions(cmd commands.Command, next commands.Handler) commands.Handler {
+	return func(_ core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+		guild, err := s.State.Guild(i.GuildID)
+		if err != nil {
+			bs.failInteraction(s, i, "No se pudo obtener guild", "err", err, "guildID", i.GuildID)
+			return
+		}
+		policy := cmd.Policy(i)
+		var dp core.Player
+		if policy == commands.PolicyRequester {
+			if e, ok := bs.players.get(i.GuildID); ok {
+				dp = e.player
+			}
+		}
+		userID := i.Member.User.ID
+		acc, reason := bs.checkPermission(cmd, policy, guild, i.Member, userVoiceChannel(guild, userID), dp, commands.Lang(i))
+		switch acc {
+		case accessVote:
+			vote := func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+				bs.Log.Info("Voto para saltar", "userID", userID, "guildID", i.GuildID)
+				commands.VoteSkipCommand(dp, s, i, bs.requiredVotes(guild, dp.VoiceChannel()))
+			}
+			bs.withPlayer(cmd, vote)(nil, s, i)
+		case accessDenied:
+			bs.Log.Info("Comando denegado", "cmd", cmd.Definition().Name, "userID", userID, "guildID", i.GuildID)
+			respondEphemeral(s, i, reason)
+		default:
+			next(nil, s, i)
+		}
+	}
+}
+
+// failInteraction registra un error bajo un código nuevo y se lo da al
+// usuario, para que la interacción no se quede sin respuesta.
+func (bs *BotServer) failInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, msg string, args ...any) {
+	errorID := newErrorID()
+	bs.Log.Error(msg, append(args, "errorID", errorID)...)
+	reportError(s, i, i18n.T(commands.Lang(i), "error.internal", errorID))
+}
+
+// newErrorID genera el código con que se cita un error en los logs.
+func newErrorID() string {
+	return fmt.Sprintf("%08x", rand.Uint32())
+}
+
+// logPanic registra un pánico con el contexto de la interacción y la traza,
+// y devuelve el código de error con que encontrarlo.
+func (bs *BotServer) logPanic(name string, r any, i *discordgo.InteractionCreate) string {
+	errorID := newErrorID()
+	bs.Log.Error("Pánico en un comando", "errorID", errorID, "cmd", name, "panic", r,
+		"interactionID", i.ID, "type", i.Type.String(), "guildID", i.GuildID, "channelID", i.ChannelID,
+		"userID", interactionUserID(i), "input", interactionInput(i), "stack", string(debug.Stack()))
+	return errorID
+}
+
+// interactionUserID devuelve quién usó la interacción: en un guild viene en
+// Member y en un DM en User.
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	switch {
+	case i.Member != nil && i.Member.User != nil:
+		return i.Member.User.ID
+	case i.User != nil:
+		return i.User.ID
+	}
+	return ""
+}
+
+// interactionInput resume lo que mandó el usuario: las opciones del comando
+// (name=valor) o el custom ID del botón.
+func interactionInput(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		var parts []string
+		var walk func(opts []*discordgo.ApplicationCommandInteractionDataOption)
+		walk = func(opts []*discordgo.ApplicationCommandInteractionDataOption) {
+			for _, opt := range opts {
+				if len(opt.Options) > 0 {
+					parts = append(parts, opt.Name)
+					walk(opt.Options)
+					continue
+				}
+				parts = append(parts, fmt.Sprintf("%s=%v", opt.Name, opt.Value))
+			}
+		}
+		walk(i.ApplicationCommandData().Options)
+		return strings.Join(parts, " ")
+	case discordgo.InteractionMessageComponent:
+		return i.MessageComponentData().CustomID
+	}
+	return ""
+}
+
+// userVoiceChannel devuelve el canal de voz del usuario, o "".
+func userVoiceChannel(guild *discordgo.Guild, userID string) string {
+	for _, vs := range guild.VoiceStates {
+		if vs.UserID == userID {
+			return vs.ChannelID
+		}
+	}
+	return ""
+}
+
+// respondEphemeral responde con un mensaje que sólo ve quien usó el comando,
+// sin notificar las menciones que lleve (p. ej. el rol DJ en un rechazo).
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:         content,
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
+// reportError avisa al usuario de un fallo. Si la interacción ya tenía
+// respuesta (p. ej. diferida), lo hace con un mensaje de seguimiento.
+func reportError(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content:         content,
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+	if err != nil {
+		_, _ = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+			Content:         content,
+			Flags:           discordgo.MessageFlagsEphemeral,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package botserver
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/commands"
+	"feints/internal/core"
+	"feints/internal/i18n"
+	"feints/internal/infra"
+)
+
+// votePlayer es un player que suena en channel y sólo anota los votos; el
+// resto de core.Player no se usa.
+type votePlayer struct {
+	core.Player
+	channel string
+	votes   []string
+}
+
+func (p *votePlayer) Current() (*core.Song, time.Duration) {
+	return &core.Song{Title: "song", RequesterID: "alice"}, 0
+}
+func (p *votePlayer) VoiceChannel() string { return p.channel }
+func (p *votePlayer) State() string        { return "playing" }
+func (p *votePlayer) ListQueue() []*core.Song {
+	return nil
+}
+func (p *votePlayer) VoteSkip(userID string, required int) (int, bool) {
+	p.votes = append(p.votes, userID)
+	return len(p.votes), false
+}
+
+// testSession crea una sesión que no sale a la red, con el guild guildID en
+// su estado y esos estados de voz; el bot es "bot". Lo que responde queda en
+// reply.
+func testSession(t *testing.T, guildID string, reply *string, states ...*discordgo.VoiceState) *discordgo.Session {
+	t.Helper()
+	s, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Client = &http.Client{Transport: captureReplies(func(r *http.Request) {
+		var body struct {
+			Data struct {
+				Content string `json:"content"`
+			} `json:"data"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*reply = body.Data.Content
+	})}
+	s.State.User = &discordgo.User{ID: "bot"}
+	if err := s.State.GuildAdd(&discordgo.Guild{ID: guildID, VoiceStates: states}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func testVoice(guildID, userID, channelID string) *discordgo.VoiceState {
+	return &discordgo.VoiceState{GuildID: guildID, UserID: userID, ChannelID: channelID,
+		Member: &discordgo.Member{User: &discordgo.User{ID: userID, Bot: userID == "bot"}}}
+}
+
+// testInteraction es el comando name usado por userID, con options.
+func testInteraction(guildID, userID, name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID: "1", Token: "token", Type: discordgo.InteractionApplicationCommand,
+		GuildID: guildID, ChannelID: "text",
+		Member: &discordgo.Member{User: &discordgo.User{ID: userID}},
+		Data:   discordgo.ApplicationCommandInteractionData{Name: name, Options: options},
+	}}
+}
+
+// captureReplies hace que s mande sus peticiones a Discord a fn en vez de a
+// la red.
+type captureReplies func(r *http.Request)
+
+func (fn captureReplies) RoundTrip(r *http.Request) (*http.Response, error) {
+	fn(r)
+	return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: r}, nil
+}
+
+func TestVoteSkipRequiresBotChannel(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const guildID = "1000"
+	// Con rol DJ y voto activado, quien no es DJ ni pidió la canción vota
+	if _, err := infra.GlobalSettings.Set(guildID, core.SettingDJRole, "42"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := infra.GlobalSettings.Set(guildID, core.SettingVoteSkip, "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	var reply string
+	s := testSession(t, guildID, &reply, testVoice(guildID, "bot", "vc1"), testVoice(guildID, "alice", "vc1"),
+		testVoice(guildID, "bob", "vc1"), testVoice(guildID, "mallory", "vc2"))
+
+	bs := NewBotServer(1, slog.New(slog.DiscardHandler))
+	bs.shards[0] = &shard{session: s}
+	dp := &votePlayer{channel: "vc1"}
+	bs.players.entries[guildID] = &playerEntry{player: dp}
+
+	skip, _ := commands.Lookup("skip")
+
+	tests := []struct {
+		userID    string
+		wantVotes []string
+		wantReply func(lang i18n.Lang) string
+	}{
+		{"mallory", nil, func(lang i18n.Lang) string { return i18n.T(lang, "voice.other_channel", "vc1") }},
+		{"carol", nil, func(lang i18n.Lang) string { return i18n.T(lang, "not_in_voice") }},
+		{"bob", []string{"bob"}, func(lang i18n.Lang) string { return i18n.T(lang, "vote.registered", 1, 1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			dp.votes, reply = nil, ""
+			i := testInteraction(guildID, tt.userID, "skip")
+			bs.dispatch(skip, skip.Execute, s, i)
+			if !slices.Equal(dp.votes, tt.wantVotes) {
+				t.Errorf("votos = %v, want %v", dp.votes, tt.wantVotes)
+			}
+			if want := tt.wantReply(commands.Lang(i)); reply != want {
+				t.Errorf("respuesta = %q, want %q", reply, want)
+			}
+		})
+	}
+}
+
+// TestPlaylistPolicy comprueba que /playlist list es de todos y save, de DJ.
+func TestPlaylistPolicy(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const guildID = "1000"
+	if _, err := infra.GlobalSettings.Set(guildID, core.SettingDJRole, "42"); err != nil {
+		t.Fatal(err)
+	}
+	var reply string
+	s := testSession(t, guildID, &reply, testVoice(guildID, "bot", "vc1"), testVoice(guildID, "alice", "vc1"))
+	bs := NewBotServer(1, slog.New(slog.DiscardHandler))
+	bs.shards[0] = &shard{session: s}
+	playlist, _ := commands.Lookup("playlist")
+
+	sub := func(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.ApplicationCommandInteractionDataOption {
+		return &discordgo.ApplicationCommandInteractionDataOption{
+			Type: discordgo.ApplicationCommandOptionSubCommand, Name: name, Options: options}
+	}
+	title := &discordgo.ApplicationCommandInteractionDataOption{
+		Type: discordgo.ApplicationCommandOptionString, Name: "title", Value: "mix"}
+
+	tests := []struct {
+		name       string
+		sub        *discordgo.ApplicationCommandInteractionDataOption
+		wantPolicy commands.Policy
+		wantReply  func(lang i18n.Lang) string
+	}{
+		{"list", sub("list"), commands.PolicyAnyone, func(lang i18n.Lang) string { return i18n.T(lang, "playlist.none") }},
+		{"save", sub("save", title), commands.PolicyDJ, func(lang i18n.Lang) string { return i18n.T(lang, "perm.dj", "42", "playlist") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply = ""
+			i := testInteraction(guildID, "carol", "playlist", tt.sub)
+			if got := playlist.Policy(i); got != tt.wantPolicy {
+				t.Errorf("Policy() = %v, want %v", got, tt.wantPolicy)
+			}
+			bs.dispatch(playlist, playlist.Execute, s, i)
+			if want := tt.wantReply(commands.Lang(i)); reply != want {
+				t.Errorf("respuesta = %q, want %q", reply, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package botserver
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/commands"
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+const (
+	// nowPlayingPoll es cada cuánto se mira si ha cambiado la canción.
+	nowPlayingPoll = time.Second
+	// nowPlayingRefresh es cada cuánto se edita el mensaje para que avance
+	// la barra de progreso (Discord limita las ediciones por canal).
+	nowPlayingRefresh = 5 * time.Second
+)
+
+// nowPlaying mantiene el mensaje "now playing" de un player: lo publica al
+// empezar a sonar, lo edita con cada canción y con el progreso, y lo borra
+// cuando la reproducción se para.
+type nowPlaying struct {
+	session *discordgo.Session
+	dp      core.Player
+	guildID string
+	log     *slog.Logger
+	closed  chan struct{}
+
+	mu        sync.Mutex
+	channelID string
+	messageID string
+	song      *core.Song // canción mostrada
+	edited    time.Time
+}
+
+func newNowPlaying(s *discordgo.Session, dp core.Player, guildID string, log *slog.Logger) *nowPlaying {
+	return &nowPlaying{
+		session: s,
+		dp:      dp,
+		guildID: guildID,
+		log:     log.With("component", "NowPlaying", "guild", guildID),
+		closed:  make(chan struct{}),
+	}
+}
+
+// Attach fija el canal del mensaje: el canal de música configurado o, si no
+// hay, channelID (donde se pidió la reproducción). Un mensaje ya publicado
+// se queda donde está hasta que se pare la reproducción.
+func (np *nowPlaying) Attach(channelID string) {
+	if music := infra.GlobalSettings.Get(np.guildID).String(core.SettingMusicChannel); music != "" {
+		channelID = music
+	}
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	if np.messageID == "" {
+		np.channelID = channelID
+	}
+}
+
+// run refresca el mensaje hasta que se cierre stop o se llame a Close.
+func (np *nowPlaying) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(nowPlayingPoll)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			np.refresh()
+		case <-stop:
+			return
+		case <-np.closed:
+			return
+		}
+	}
+}
+
+// Announce publica un aviso en el canal del mensaje, si hay reproducción.
+func (np *nowPlaying) Announce(content string) {
+	np.mu.Lock()
+	channelID := np.channelID
+	np.mu.Unlock()
+	if channelID == "" {
+		return
+	}
+	if _, err := np.session.ChannelMessageSend(channelID, content); err != nil {
+		np.log.Warn("No se pudo publicar el aviso", "err", err, "channelID", channelID)
+	}
+}
+
+// Close borra el mensaje y detiene run; se usa al retirar el player.
+func (np *nowPlaying) Close() {
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	np.remove()
+	close(np.closed)
+}
+
+func (np *nowPlaying) refresh() {
+	np.mu.Lock()
+	defer np.mu.Unlock()
+	if np.channelID == "" {
+		return
+	}
+
+	song, _ := np.dp.Current()
+	if song == nil {
+		// Entre canciones Current es nil un momento; sólo se borra el
+		// mensaje cuando ya no queda nada que reproducir
+		if np.dp.State() == "idle" && len(np.dp.ListQueue()) == 0 {
+			np.remove()
+		}
+		return
+	}
+	if song == np.song && time.Since(np.edited) < nowPlayingRefresh {
+		return
+	}
+
+	guild, err := np.session.State.Guild(np.guildID)
+	if err != nil {
+		return
+	}
+	embed := commands.NowPlayingEmbed(commands.GuildLang(guild), np.dp)
+	if embed == nil {
+		return
+	}
+	embeds := []*discordgo.MessageEmbed{embed}
+	components := commands.PlayerButtons(np.dp)
+
+	if np.messageID != "" {
+		_, err = np.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         np.messageID,
+			Channel:    np.channelID,
+			Embeds:     &embeds,
+			Components: &components,
+		})
+		if err == nil {
+			np.song, np.edited = song, time.Now()
+			return
+		}
+		if !isNotFound(err) {
+			np.log.Warn("No se pudo editar el mensaje", "err", err)
+			return
+		}
+		// Alguien borró el mensaje: se publica otro
+	}
+
+	msg, err := np.session.ChannelMessageSendComplex(np.channelID, &discordgo.MessageSend{
+		Embeds:          embeds,
+		Components:      components,
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		np.log.Warn("No se pudo publicar el mensaje", "err", err, "channelID", np.channelID)
+		return
+	}
+	np.messageID, np.song, np.edited = msg.ID, song, time.Now()
+}
+
+// remove borra el mensaje y olvida el canal; requiere np.mu tomado.
+func (np *nowPlaying) remove() {
+	if np.messageID != "" {
+		if err := np.session.ChannelMessageDelete(np.channelID, np.messageID); err != nil && !isNotFound(err) {
+			np.log.Warn("No se pudo borrar el mensaje", "err", err)
+		}
+	}
+	np.channelID, np.messageID, np.song = "", "", nil
+}
+
+// isNotFound indica si Discord respondió que el recurso ya no existe.
+func isNotFound(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Response != nil &&
+		restErr.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,115 @@
+package botserver
+
+import (
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/commands"
+	"feints/internal/core"
+	"feints/internal/i18n"
+	"feints/internal/infra"
+)
+
+// access es el resultado de comprobar los permisos de un comando.
+type access int
+
+const (
+	accessDenied access = iota
+	accessGranted
+	// accessVote: el usuario no puede saltar directamente, pero sí votar.
+	accessVote
+)
+
+// checkPermission decide si el miembro puede ejecutar cmd con la política
+// policy que le toca a la interacción. Los
+// administradores siempre pueden y quien está a solas con el bot tiene
+// control total salvo en comandos de administración. En comandos
+// PolicyRequester, si el guild usa votación, el resto puede votar.
+// Devuelve el motivo del rechazo, en lang, para mostrárselo al usuario.
+func (bs *BotServer) checkPermission(cmd commands.Command, policy commands.Policy, guild *discordgo.Guild, member *discordgo.Member,
+	voiceChannelID string, dp core.Player, lang i18n.Lang) (access, string) {
+
+	name := cmd.Definition().Name
+	if policy == commands.PolicyAnyone || isAdmin(member) {
+		return accessGranted, ""
+	}
+	if policy == commands.PolicyAdmin {
+		return accessDenied, i18n.T(lang, "perm.admin_only", name)
+	}
+	if bs.aloneWithBot(guild, member.User.ID, voiceChannelID) {
+		return accessGranted, ""
+	}
+
+	settings := infra.GlobalSettings.Get(guild.ID)
+	djRole := settings.String(core.SettingDJRole)
+	// Sin rol DJ configurado todos son DJ
+	if djRole == "" || slices.Contains(member.Roles, djRole) {
+		return accessGranted, ""
+	}
+
+	if policy == commands.PolicyRequester && dp != nil {
+		if song, _ := dp.Current(); song != nil && song.RequesterID == member.User.ID {
+			return accessGranted, ""
+		}
+		if settings.Bool(core.SettingVoteSkip) {
+			return accessVote, ""
+		}
+		return accessDenied, i18n.T(lang, "perm.requester_or_dj", djRole, name)
+	}
+	return accessDenied, i18n.T(lang, "perm.dj", djRole, name)
+}
+
+// requiredVotes calcula los votos necesarios para saltar: el porcentaje
+// configurado de los oyentes del canal, como mínimo uno.
+func (bs *BotServer) requiredVotes(guild *discordgo.Guild, channelID string) int {
+	percent := infra.GlobalSettings.Get(guild.ID).Int(core.SettingVoteSkipPercent)
+	return max(1, (bs.listeners(guild, channelID)*percent+99)/100)
+}
+
+// listeners cuenta los miembros (no bots) de un canal de voz.
+func (bs *BotServer) listeners(guild *discordgo.Guild, channelID string) int {
+	n := 0
+	for _, vs := range guild.VoiceStates {
+		if vs.ChannelID == channelID && !bs.isBot(guild.ID, vs) {
+			n++
+		}
+	}
+	return n
+}
+
+// aloneWithBot indica si el usuario es el único humano en el canal de voz
+// del bot.
+func (bs *BotServer) aloneWithBot(guild *discordgo.Guild, userID, channelID string) bool {
+	botID := bs.sessionFor(guild.ID).State.User.ID
+	botHere := false
+	for _, vs := range guild.VoiceStates {
+		if vs.ChannelID != channelID {
+			continue
+		}
+		switch {
+		case vs.UserID == botID:
+			botHere = true
+		case vs.UserID == userID:
+		case !bs.isBot(guild.ID, vs):
+			return false
+		}
+	}
+	return botHere
+}
+
+// isBot indica si el dueño de un estado de voz es un bot.
+func (bs *BotServer) isBot(guildID string, vs *discordgo.VoiceState) bool {
+	if vs.Member != nil && vs.Member.User != nil {
+		return vs.Member.User.Bot
+	}
+	if m, err := bs.sessionFor(guildID).State.Member(guildID, vs.UserID); err == nil && m.User != nil {
+		return m.User.Bot
+	}
+	return false
+}
+
+func isAdmin(m *discordgo.Member) bool {
+	const admin = discordgo.PermissionAdministrator | discordgo.PermissionManageGuild
+	return m.Permissions&admin != 0
+}
@@ -0,0 +1,55 @@
+package botserver
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitBurst comandos por usuario caben en cada rateLimitWindow.
+	rateLimitBurst  = 5
+	rateLimitWindow = 10 * time.Second
+)
+
+// limiter cuenta los comandos recientes de cada usuario (ventana deslizante).
+type limiter struct {
+	mu     sync.Mutex
+	recent map[string][]time.Time
+}
+
+func newLimiter() *limiter {
+	return &limiter{recent: make(map[string][]time.Time)}
+}
+
+// allow apunta un comando del usuario y devuelve 0 si puede ejecutarlo o,
+// si ha superado el límite, cuánto le falta para poder.
+func (l *limiter) allow(userID string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+
+	var kept []time.Time
+	for _, t := range l.recent[userID] {
+		if now.Sub(t) < rateLimitWindow {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) >= rateLimitBurst {
+		l.recent[userID] = kept
+		return rateLimitWindow - now.Sub(kept[0])
+	}
+	l.recent[userID] = append(kept, now)
+	return 0
+}
+
+// prune olvida a los usuarios sin comandos recientes.
+func (l *limiter) prune() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for userID, times := range l.recent {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= rateLimitWindow {
+			delete(l.recent, userID)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package botserver
+
+import (
+	"sync"
+
+	"feints/internal/core"
+)
+
+// playerEntry es un player activo con su mensaje "now playing" y lo que el
+// janitor sabe de él.
+type playerEntry struct {
+	player     core.Player
+	nowPlaying *nowPlaying
+	presence   presence
+}
+
+// registry guarda los players activos, uno por guild. Los handlers de
+// discordgo corren cada uno en su goroutine, así que todo acceso pasa por mu.
+type registry struct {
+	mu      sync.Mutex
+	entries map[string]*playerEntry
+}
+
+func newRegistry() *registry {
+	return &registry{entries: make(map[string]*playerEntry)}
+}
+
+// get devuelve la entrada del guild, si existe.
+func (r *registry) get(guildID string) (*playerEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[guildID]
+	return e, ok
+}
+
+// getOrCreate devuelve la entrada del guild o guarda la que devuelva create.
+// create se llama con el registro bloqueado, así que dos comandos a la vez
+// no pueden crear dos players para el mismo guild.
+func (r *registry) getOrCreate(guildID string, create func() *playerEntry) (e *playerEntry, created bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.entries[guildID]; ok {
+		return e, false
+	}
+	e = create()
+	r.entries[guildID] = e
+	metricPlayersCreated.Add(1)
+	metricPlayersActive.Set(int64(len(r.entries)))
+	return e, true
+}
+
+// remove quita la entrada del guild y la devuelve para cerrarla.
+func (r *registry) remove(guildID string) (*playerEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[guildID]
+	if ok {
+		delete(r.entries, guildID)
+		metricPlayersClosed.Add(1)
+		metricPlayersActive.Set(int64(len(r.entries)))
+	}
+	return e, ok
+}
+
+// snapshot copia las entradas para recorrerlas sin tener el registro
+// bloqueado.
+func (r *registry) snapshot() map[string]*playerEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]*playerEntry, len(r.entries))
+	for guildID, e := range r.entries {
+		out[guildID] = e
+	}
+	return out
+}
+
+// withPresence ejecuta fn sobre el estado del janitor de una entrada con el
+// registro bloqueado.
+func (r *registry) withPresence(e *playerEntry, fn func(p *presence)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&e.presence)
+}
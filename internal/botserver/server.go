@@ -1,14 +1,18 @@
 package botserver
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"strings"
+	"sync/atomic"
 
+	"feints/config"
 	"feints/internal/commands"
-	"feints/internal/infra"
 	"feints/internal/core"
+	"feints/internal/i18n"
+	"feints/internal/infra"
 
 	"github.com/bwmarrin/discordgo"
 	"log/slog"
@@ -16,175 +20,198 @@ import (
 
 // BotServer administra múltiples reproductores por guild
 type BotServer struct {
-	session *discordgo.Session
-	Log     *slog.Logger
-	players	map[string]core.Player
+	// shards son las sesiones de este proceso por número de shard; se
+	// rellenan antes de conectar ninguna y no cambian después
+	shards     map[int]*shard
+	shardCount int
+	Log        *slog.Logger
+	players    *registry
+	limiter    *limiter
+	done       chan struct{}
+	// closing se activa al empezar el apagado: no se aceptan más comandos
+	closing atomic.Bool
 }
 
 // NewBotServer crea un nuevo servidor de bots con logger JSON
-func NewBotServer(s *discordgo.Session, logger *slog.Logger) *BotServer {
+func NewBotServer(shardCount int, logger *slog.Logger) *BotServer {
 
 	return &BotServer{
-		session: s,
-		Log:     logger,
-		players: make(map[string]core.Player),
+		shards:     make(map[int]*shard),
+		shardCount: shardCount,
+		Log:        logger,
+		players:    newRegistry(),
+		limiter:    newLimiter(),
+		done:       make(chan struct{}),
 	}
 }
 
+// GetOrCreatePlayer devuelve el player del guild (Discord sólo permite una
+// conexión de voz por guild) o crea uno en channelID. Un player existente
+// sigue en su canal salvo que esté parado y sin cola: entonces se va al canal
+// de quien lo llama.
 func (bs *BotServer) GetOrCreatePlayer(guildID, channelID string) (core.Player, error) {
-    key := guildID + "_" + channelID
-
-    // si ya existe, devolverlo
-    if player, ok := bs.players[key]; ok {
-        bs.Log.Info("Player encontrado", "guildID", guildID, "channelID", channelID)
-        return player, nil
-    }
-
-    // crear uno nuevo
-    dp := infra.NewDgvoicePlayer(bs.session, guildID, channelID, bs.Log)
-    bs.players[key] = dp
+	e, created := bs.players.getOrCreate(guildID, func() *playerEntry {
+		session := bs.sessionFor(guildID)
+		dp := infra.NewDgvoicePlayer(session, guildID, channelID, bs.Log)
+		np := newNowPlaying(session, dp, guildID, bs.Log)
+		go np.run(bs.done)
+		dp.OnVoiceFailure(func(error) {
+			if guild, err := session.State.Guild(guildID); err == nil {
+				np.Announce(i18n.T(commands.GuildLang(guild), "voice.failed"))
+			}
+		})
+		dp.OnSongFailure(func(song core.Song, songErr error) {
+			if guild, err := session.State.Guild(guildID); err == nil {
+				lang := commands.GuildLang(guild)
+				title := cmp.Or(song.Title, song.URL)
+				np.Announce(i18n.T(lang, "song.skipped", title, commands.SongErrorMessage(lang, songErr)))
+			}
+		})
+		return &playerEntry{player: dp, nowPlaying: np}
+	})
+	if created {
+		bs.Log.Info("Player creado", "guildID", guildID, "channelID", channelID)
+		return e.player, nil
+	}
 
-    bs.Log.Info("Player creado", "guildID", guildID, "channelID", channelID)
-    return dp, nil
+	player := e.player
+	if player.VoiceChannel() != channelID && player.State() == "idle" && len(player.ListQueue()) == 0 {
+		if err := player.MoveTo(channelID); err != nil {
+			return nil, err
+		}
+	}
+	bs.Log.Info("Player encontrado", "guildID", guildID, "channelID", player.VoiceChannel())
+	return player, nil
 }
 
-
-// HandleCommand despacha las interacciones a los comandos
-func (bs *BotServer) HandleCommand(cmd string, s *discordgo.Session, i *discordgo.InteractionCreate) {
-	userID := i.Member.User.ID
-	guildID := i.GuildID
-
-	// Buscar canal de voz del usuario
-	var voiceChannelID string
-	guild, err := s.State.Guild(guildID)
-	if err != nil {
-		bs.Log.Error("No se pudo obtener guild", "err", err, "guildID", guildID)
+// HandleVoiceState sigue al bot cuando un moderador lo arrastra a otro canal
+// (el player se mueve con él, con la cola intacta), para la reproducción si
+// lo desconectan y, con cualquier entrada o salida, pausa o reanuda según
+// queden oyentes (ver watchListeners).
+func (bs *BotServer) HandleVoiceState(s *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+	e, ok := bs.players.get(v.GuildID)
+	if !ok {
 		return
 	}
-	for _, vs := range guild.VoiceStates {
-		if vs.UserID == userID {
-			voiceChannelID = vs.ChannelID
-			break
+	dp := e.player
+	if v.UserID == s.State.User.ID && v.ChannelID != dp.VoiceChannel() {
+		if v.ChannelID == "" {
+			if dp.State() != "idle" {
+				bs.Log.Info("Bot desconectado del canal de voz", "guildID", v.GuildID)
+				dp.Stop()
+			}
+			return
+		}
+		bs.Log.Info("Bot movido de canal", "guildID", v.GuildID, "channelID", v.ChannelID)
+		if err := dp.MoveTo(v.ChannelID); err != nil {
+			bs.Log.Error("No se pudo seguir al nuevo canal", "err", err, "guildID", v.GuildID)
 		}
 	}
-	if voiceChannelID == "" {
-		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "❌ No estás en un canal de voz.",
-			},
-		})
+	bs.watchListeners(v.GuildID, e)
+}
+
+// HandleAutocomplete responde a las sugerencias de un comando. Un pánico sólo
+// se registra: el usuario se queda sin sugerencias.
+func (bs *BotServer) HandleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	cmd, ok := commands.Lookup(i.ApplicationCommandData().Name)
+	if !ok {
 		return
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			bs.logPanic(cmd.Definition().Name, r, i)
+		}
+	}()
+	cmd.Autocomplete(s, i)
+}
 
-	// Obtener el player
-	dp, err := bs.GetOrCreatePlayer(guildID, voiceChannelID)
-	if err != nil {
-		bs.Log.Error("Error obteniendo player", "err", err)
+// HandleCommand ejecuta un comando de barra a través de los middleware.
+func (bs *BotServer) HandleCommand(name string, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if bs.rejectIfClosing(s, i) {
+		return
+	}
+	cmd, ok := commands.Lookup(name)
+	if !ok {
+		bs.Log.Warn("Comando desconocido", "cmd", name)
 		return
 	}
+	bs.dispatch(cmd, cmd.Execute, s, i)
+}
 
-	bs.Log.Info("Ejecutando comando", "cmd", cmd, "userID", userID, "guildID", guildID)
-
-	switch cmd {
-	case "play":
-		commands.PlayCommand(dp, s, i)
-	case "pause":
-		// commands.PauseCommand(dp, s, i)
-	case "stop":
-		commands.StopCommand(dp, s, i)
-	case "queue":
-		commands.QueueCommand(dp, s, i)
-	case "skip", "next":
-		commands.SkipCommand(dp, s, i)
-	case "clear":
-		commands.ClearCommand(dp, s, i)
-	case "status":
-		commands.StatusCommand(dp, s, i)
-	case "test":
-		commands.TestCommand(dp, s, i)
-	case "autoplay":
-		commands.AutoPlay(dp,s,i)
+// HandleComponent despacha los botones de los mensajes del bot: los del
+// "now playing" y la paginación de /queue. Cada botón pasa por los mismos
+// middleware (y permisos) que el comando equivalente.
+func (bs *BotServer) HandleComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if bs.rejectIfClosing(s, i) {
+		return
+	}
+	customID := i.MessageComponentData().CustomID
+	if action, ok := strings.CutPrefix(customID, commands.PlayerButtonPrefix); ok {
+		if cmd, ok := commands.Lookup(action); ok {
+			bs.dispatch(cmd, func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+				commands.PlayerButton(dp, s, i, action)
+			}, s, i)
+		}
+		return
+	}
+	if page, ok := strings.CutPrefix(customID, commands.QueuePageButtonPrefix); ok {
+		if cmd, ok := commands.Lookup("queue"); ok {
+			bs.dispatch(cmd, func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+				commands.QueuePageButton(dp, s, i, page)
+			}, s, i)
+		}
 	}
 }
 
-// Run inicializa el bot y maneja los eventos
-func Run(log*slog.Logger) error {
+// Run conecta los shards de este proceso y maneja los eventos hasta que se
+// cancele ctx; entonces apaga los players (ver Shutdown) con
+// SHUTDOWN_TIMEOUT de margen.
+func Run(ctx context.Context, log *slog.Logger) error {
+	if config.Err != nil {
+		return fmt.Errorf("configuración inválida: %w", config.Err)
+	}
 	token := os.Getenv("DISCORD_TOKEN")
 	if token == "" {
 		return fmt.Errorf("DISCORD_BOT_TOKEN no está definido")
 	}
-	dg, err := discordgo.New("Bot " + token)
-	if err != nil {
-		return fmt.Errorf("error creando sesión de Discord: %v", err)
-	}
-	
 	log = log.With("component", "BotServer")
 
-	// Handler de Ready
-	dg.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
-		log.Info("Bot conectado", "username", s.State.User.Username)
-
-		commandsToRegister := []*discordgo.ApplicationCommand{
-			{
-				Name:        "play",
-				Description: "Reproduce una canción",
-				Options: []*discordgo.ApplicationCommandOption{
-					{
-						Type:         discordgo.ApplicationCommandOptionString,
-						Name:         "search",
-						Description:  "Nombre o URL de la canción",
-						Required:     true,
-						Autocomplete: true,
-					},
-				},
-			},
-			{Name: "stop", Description: "Detiene la reproducción y se desconecta"},
-			{Name: "queue", Description: "Muestra la cola de canciones"},
-			{Name: "skip", Description: "Salta a la siguiente canción"},
-			{Name: "clear", Description: "Limpia la cola"},
-			{Name: "status", Description: "Muestra el estado actual"},
-			{Name: "test", Description: "Prueba de carga en la cola"},
-			{Name: "autoplay", Description: "Activa autoplay"},
-		}
+	if api := config.Global.GatewayAPI; api != "" {
+		setGatewayAPI(api)
+		log.Warn("Usando un gateway alternativo", "endpoint", discordgo.EndpointGateway)
+	}
 
-		for _, cmd := range commandsToRegister {
-			_, err := s.ApplicationCommandCreate(s.State.User.ID, "", cmd)
-			if err != nil {
-				log.Error("Error registrando comando", "name", cmd.Name, "err", err)
-			} else {
-				log.Info("Comando registrado", "name", cmd.Name)
-			}
+	bs := NewBotServer(config.Global.ShardCount, log)
+	for _, id := range config.Global.ShardIDs {
+		dg, err := discordgo.New("Bot " + token)
+		if err != nil {
+			return fmt.Errorf("error creando sesión de Discord: %v", err)
 		}
-	})
+		dg.ShardID, dg.ShardCount = id, config.Global.ShardCount
+		bs.addShard(id, dg)
+	}
+	publishShardMetrics(bs)
 
-	// Autocompletado
-	dg.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type == discordgo.InteractionApplicationCommandAutocomplete {
-			switch i.ApplicationCommandData().Name {
-			case "play":
-				commands.SearchCommand(s, i)
-			}
-		}
-	})
-	if err := dg.Open(); err != nil {
+	if err := bs.openShards(ctx); err != nil {
 		return err
 	}
-	bs := NewBotServer(dg, log)
-	// Comandos
-	dg.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type == discordgo.InteractionApplicationCommand {
-			bs.HandleCommand(i.ApplicationCommandData().Name, s, i)
-		}
-	})
+	defer bs.closeShards()
+	defer close(bs.done)
 
-	StartJanitor(dg)
-	defer dg.Close()
+	go bs.StartJanitor(bs.done)
+	go bs.monitorShards(bs.done)
+	if addr := config.Global.MetricsAddr; addr != "" {
+		go serveMetrics(addr, log)
+	}
 
-	bs.Log.Info("Bot ejecutándose. Presiona CTRL+C para salir.")
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	<-stop
+	bs.Log.Info("Bot ejecutándose. Presiona CTRL+C para salir.",
+		"shards", config.Global.ShardIDs, "shardCount", config.Global.ShardCount)
+	<-ctx.Done()
 
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Global.ShutdownTimeout)
+	defer cancel()
+	if err := bs.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("apagado incompleto: %w", err)
+	}
 	return nil
 }
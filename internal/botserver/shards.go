@@ -0,0 +1,190 @@
+package botserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/config"
+)
+
+// shardHealthInterval es cada cuánto se registra el estado de los shards.
+const shardHealthInterval = time.Minute
+
+// shardOpenDelay separa la conexión de cada shard: Discord sólo admite un
+// IDENTIFY cada 5 segundos. Las pruebas contra el gateway falso lo anulan.
+var shardOpenDelay = 5 * time.Second
+
+// shard es una sesión del gateway. Atiende los guilds con
+// (guildID >> 22) % ShardCount == id.
+type shard struct {
+	id         int
+	session    *discordgo.Session
+	connected  atomic.Bool
+	reconnects atomic.Int64
+}
+
+// shardStat es el estado de un shard en las métricas y en los logs.
+type shardStat struct {
+	Connected  bool  `json:"connected"`
+	Guilds     int   `json:"guilds"`
+	LatencyMS  int64 `json:"latency_ms"`
+	Reconnects int64 `json:"reconnects"`
+}
+
+// setGatewayAPI hace que las sesiones descubran el gateway en api (p. ej. un
+// gateway falso local) en lugar de en Discord.
+func setGatewayAPI(api string) {
+	discordgo.EndpointGateway = strings.TrimSuffix(api, "/") + "/gateway"
+	discordgo.EndpointGatewayBot = discordgo.EndpointGateway + "/bot"
+}
+
+// shardFor devuelve el shard que atiende un guild.
+func shardFor(guildID string, count int) int {
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil || count <= 1 {
+		return 0
+	}
+	return int((id >> 22) % uint64(count))
+}
+
+// sessionFor devuelve la sesión del shard del guild. Los eventos de un guild
+// sólo llegan por su shard, así que si lo vemos es que es de este proceso;
+// por si acaso se cae en el primero.
+func (bs *BotServer) sessionFor(guildID string) *discordgo.Session {
+	if sh, ok := bs.shards[shardFor(guildID, bs.shardCount)]; ok {
+		return sh.session
+	}
+	return bs.shards[bs.shardIDs()[0]].session
+}
+
+// shardIDs devuelve los shards de este proceso en orden.
+func (bs *BotServer) shardIDs() []int {
+	ids := make([]int, 0, len(bs.shards))
+	for id := range bs.shards {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// addShard añade la sesión del shard id y le engancha los handlers del bot.
+// Se llama antes de openShards.
+func (bs *BotServer) addShard(id int, s *discordgo.Session) {
+	sh := &shard{id: id, session: s}
+	bs.shards[id] = sh
+	log := bs.Log.With("shard", id)
+
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+		sh.connected.Store(true)
+		log.Info("Bot conectado", "username", s.State.User.Username, "guilds", len(r.Guilds))
+		// Los comandos son de toda la aplicación: basta con que los
+		// sincronice el shard 0
+		if id == 0 {
+			if err := syncCommands(s, config.Global.DevGuildID, log); err != nil {
+				log.Error("Error sincronizando comandos", "err", err)
+			}
+		}
+	})
+	s.AddHandler(func(s *discordgo.Session, _ *discordgo.Disconnect) {
+		sh.connected.Store(false)
+		log.Warn("Shard desconectado")
+	})
+	s.AddHandler(func(s *discordgo.Session, _ *discordgo.Resumed) {
+		sh.connected.Store(true)
+		sh.reconnects.Add(1)
+		log.Info("Shard reconectado")
+	})
+
+	s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		switch i.Type {
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			bs.HandleAutocomplete(s, i)
+		case discordgo.InteractionApplicationCommand:
+			bs.HandleCommand(i.ApplicationCommandData().Name, s, i)
+		case discordgo.InteractionMessageComponent:
+			bs.HandleComponent(s, i)
+		}
+	})
+	s.AddHandler(bs.HandleVoiceState)
+}
+
+// openShards conecta los shards uno tras otro, respetando el límite de
+// IDENTIFY. Si alguno falla cierra los ya abiertos.
+func (bs *BotServer) openShards(ctx context.Context) error {
+	for n, id := range bs.shardIDs() {
+		if n > 0 {
+			select {
+			case <-time.After(shardOpenDelay):
+			case <-ctx.Done():
+				bs.closeShards()
+				return ctx.Err()
+			}
+		}
+		if err := bs.shards[id].session.Open(); err != nil {
+			bs.closeShards()
+			return fmt.Errorf("error conectando el shard %d: %w", id, err)
+		}
+		bs.Log.Info("Shard abierto", "shard", id, "shardCount", bs.shardCount)
+	}
+	return nil
+}
+
+// closeShards cierra todas las sesiones (las no abiertas se ignoran).
+func (bs *BotServer) closeShards() {
+	for _, sh := range bs.shards {
+		_ = sh.session.Close()
+		sh.connected.Store(false)
+	}
+}
+
+// shardStats devuelve el estado de cada shard, por número.
+func (bs *BotServer) shardStats() map[string]shardStat {
+	stats := make(map[string]shardStat, len(bs.shards))
+	for id, sh := range bs.shards {
+		sh.session.State.RLock()
+		guilds := len(sh.session.State.Guilds)
+		sh.session.State.RUnlock()
+		// El latido de discordgo actualiza estos tiempos con la sesión
+		// bloqueada
+		sh.session.RLock()
+		latency := sh.session.HeartbeatLatency()
+		sh.session.RUnlock()
+		stats[strconv.Itoa(id)] = shardStat{
+			Connected:  sh.connected.Load(),
+			Guilds:     guilds,
+			LatencyMS:  latency.Milliseconds(),
+			Reconnects: sh.reconnects.Load(),
+		}
+	}
+	return stats
+}
+
+// monitorShards registra el estado de los shards hasta que se cierre stop.
+func (bs *BotServer) monitorShards(stop <-chan struct{}) {
+	ticker := time.NewTicker(shardHealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for id, stat := range bs.shardStats() {
+				level := slog.LevelInfo
+				if !stat.Connected {
+					level = slog.LevelWarn
+				}
+				bs.Log.Log(context.Background(), level, "Estado del shard", "shard", id,
+					"connected", stat.Connected, "guilds", stat.Guilds,
+					"latencyMS", stat.LatencyMS, "reconnects", stat.Reconnects)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package botserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// guildOn devuelve un id de guild (snowflake) que cae en el shard n.
+func guildOn(n, count, k int) string {
+	return strconv.Itoa((k*count + n) << 22)
+}
+
+func TestShardFor(t *testing.T) {
+	tests := []struct {
+		guildID string
+		count   int
+		want    int
+	}{
+		{guildID: "81384788765712384", count: 1, want: 0},
+		{guildID: "81384788765712384", count: 0, want: 0},
+		{guildID: "not-a-snowflake", count: 4, want: 0},
+		{guildID: "", count: 4, want: 0},
+		// 81384788765712384 >> 22 = 19403645698
+		{guildID: "81384788765712384", count: 2, want: 0},
+		{guildID: "81384788765712384", count: 7, want: 3},
+		{guildID: guildOn(3, 5, 0), count: 5, want: 3},
+		{guildID: guildOn(4, 5, 9), count: 5, want: 4},
+	}
+	for _, tt := range tests {
+		if got := shardFor(tt.guildID, tt.count); got != tt.want {
+			t.Errorf("shardFor(%q, %d) = %d, want %d", tt.guildID, tt.count, got, tt.want)
+		}
+	}
+}
+
+// fakeGateway es un gateway de Discord mínimo: responde al descubrimiento
+// (GET /gateway), manda el Hello y contesta cada IDENTIFY con un READY con
+// los guilds del shard que se identifica.
+type fakeGateway struct {
+	*httptest.Server
+	guilds []string
+
+	mu         sync.Mutex
+	identified [][2]int
+}
+
+func newFakeGateway(t *testing.T, guilds []string) *fakeGateway {
+	gw := &fakeGateway{guilds: guilds}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gateway", func(w http.ResponseWriter, r *http.Request) {
+		url := "ws" + strings.TrimPrefix(gw.URL, "http") + "/ws"
+		_ = json.NewEncoder(w).Encode(map[string]string{"url": url})
+	})
+	// discordgo añade "/" a la URL que le damos
+	mux.HandleFunc("/ws/", gw.serveWS)
+	gw.Server = httptest.NewServer(mux)
+	t.Cleanup(gw.Close)
+	return gw
+}
+
+func (gw *fakeGateway) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	hello := map[string]any{"op": 10, "d": map[string]any{"heartbeat_interval": 45000}}
+	if conn.WriteJSON(hello) != nil {
+		return
+	}
+	var identify struct {
+		Op   int `json:"op"`
+		Data struct {
+			Shard *[2]int `json:"shard"`
+		} `json:"d"`
+	}
+	if conn.ReadJSON(&identify) != nil || identify.Op != 2 {
+		return
+	}
+	shard := [2]int{0, 1}
+	if identify.Data.Shard != nil {
+		shard = *identify.Data.Shard
+	}
+	gw.mu.Lock()
+	gw.identified = append(gw.identified, shard)
+	gw.mu.Unlock()
+
+	var guilds []map[string]any
+	for _, id := range gw.guilds {
+		if shardFor(id, shard[1]) == shard[0] {
+			guilds = append(guilds, map[string]any{"id": id, "unavailable": true})
+		}
+	}
+	ready := map[string]any{
+		"op": 0, "s": 1, "t": "READY",
+		"d": map[string]any{
+			"v":          10,
+			"session_id": "fake-" + strconv.Itoa(shard[0]),
+			"user":       map[string]any{"id": "1", "username": "feints"},
+			"guilds":     guilds,
+			"shard":      shard,
+		},
+	}
+	if conn.WriteJSON(ready) != nil {
+		return
+	}
+	// Aguantar la conexión (heartbeats) hasta que la sesión se cierre
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// Un proceso que atiende los shards 1 y 2 de 3: cada sesión se identifica
+// con su shard, recibe sólo sus guilds y sessionFor enruta a la correcta.
+func TestShardsWithFakeGateway(t *testing.T) {
+	const count = 3
+	var guilds []string
+	for k := range 3 {
+		for n := range count {
+			guilds = append(guilds, guildOn(n, count, k))
+		}
+	}
+	gw := newFakeGateway(t, guilds)
+
+	gateway, gatewayBot, delay := discordgo.EndpointGateway, discordgo.EndpointGatewayBot, shardOpenDelay
+	t.Cleanup(func() {
+		discordgo.EndpointGateway, discordgo.EndpointGatewayBot, shardOpenDelay = gateway, gatewayBot, delay
+	})
+	setGatewayAPI(gw.URL)
+	shardOpenDelay = 0
+
+	bs := NewBotServer(count, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for _, id := range []int{1, 2} {
+		s, err := discordgo.New("Bot fake")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.ShardID, s.ShardCount = id, count
+		bs.addShard(id, s)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := bs.openShards(ctx); err != nil {
+		t.Fatalf("openShards: %v", err)
+	}
+	defer bs.closeShards()
+
+	gw.mu.Lock()
+	identified := slices.Clone(gw.identified)
+	gw.mu.Unlock()
+	if want := [][2]int{{1, count}, {2, count}}; !slices.Equal(identified, want) {
+		t.Errorf("IDENTIFY con shards %v, want %v", identified, want)
+	}
+
+	for _, guildID := range guilds {
+		n := shardFor(guildID, count)
+		for id, sh := range bs.shards {
+			_, err := sh.session.State.Guild(guildID)
+			if has := err == nil; has != (id == n) {
+				t.Errorf("guild %s (shard %d) en el estado del shard %d: %v", guildID, n, id, has)
+			}
+		}
+		if n != 0 && bs.sessionFor(guildID) != bs.shards[n].session {
+			t.Errorf("sessionFor(%s) no es la sesión del shard %d", guildID, n)
+		}
+	}
+
+	// El handler de READY (asíncrono) marca los shards como conectados
+	connected := func() bool {
+		for _, sh := range bs.shards {
+			if !sh.connected.Load() {
+				return false
+			}
+		}
+		return true
+	}
+	for deadline := time.Now().Add(2 * time.Second); !connected() && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	for id, stat := range bs.shardStats() {
+		if !stat.Connected || stat.Guilds != 3 {
+			t.Errorf("shard %s: %+v, want conectado con 3 guilds", id, stat)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package botserver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/commands"
+	"feints/internal/i18n"
+	"feints/internal/infra"
+)
+
+// Shutdown deja de aceptar comandos, guarda las colas de los players, avisa
+// en los canales donde sonaba algo y cierra los players (soltando la voz).
+// Si ctx vence antes de terminar devuelve su error y deja el resto a medias.
+func (bs *BotServer) Shutdown(ctx context.Context) error {
+	bs.closing.Store(true)
+	entries := bs.players.snapshot()
+	bs.Log.Info("Apagando", "players", len(entries))
+
+	var wg sync.WaitGroup
+	for guildID, e := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bs.shutdownPlayer(guildID, e)
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		bs.Log.Info("Players cerrados")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (bs *BotServer) shutdownPlayer(guildID string, e *playerEntry) {
+	saved, err := infra.SaveQueue(guildID, e.player)
+	if err != nil {
+		bs.Log.Error("No se pudo guardar la cola", "err", err, "guildID", guildID)
+	}
+	if saved > 0 {
+		bs.Log.Info("Cola guardada", "guildID", guildID, "songs", saved)
+		if guild, err := bs.sessionFor(guildID).State.Guild(guildID); err == nil {
+			e.nowPlaying.Announce(i18n.T(commands.GuildLang(guild), "shutdown.notice", saved))
+		}
+	}
+	bs.removePlayer(guildID, "shutdown")
+}
+
+// rejectIfClosing responde a la interacción si el bot se está apagando y
+// devuelve true en ese caso.
+func (bs *BotServer) rejectIfClosing(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if !bs.closing.Load() {
+		return false
+	}
+	respondEphemeral(s, i, i18n.T(commands.Lang(i), "shutdown.rejected"))
+	return true
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+// AutoPlay maneja /autoplay [mode:on|off|status] [source] [value].
+// Los cambios se guardan por guild y sobreviven a reinicios.
+func AutoPlay(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	settings := dp.AutoPlaySettings()
+	mode := "status"
+	changed := false
+
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "mode":
+			mode = opt.StringValue()
+		case "source":
+			settings.Source = core.AutoplaySource(opt.StringValue())
+			changed = true
+		case "value":
+			settings.Value = opt.StringValue()
+			changed = true
+		}
+	}
+
+	switch mode {
+	case "on":
+		settings.Enabled = true
+		changed = true
+	case "off":
+		settings.Enabled = false
+		changed = true
+	}
+
+	if settings.Source == "" {
+		settings.Source = core.AutoplaySmart
+	}
+	if !settings.Source.Valid() {
+		respond(s, i, tr(i, "autoplay.unknown_source", settings.Source))
+		return
+	}
+	if (settings.Source == core.AutoplayPlaylist || settings.Source == core.AutoplayGenre) && settings.Value == "" {
+		respond(s, i, tr(i, "autoplay.needs_value", settings.Source))
+		return
+	}
+	if settings.Source == core.AutoplayPlaylist {
+		if _, err := infra.LoadPlaylist(i.GuildID, settings.Value); err != nil {
+			respond(s, i, tr(i, "autoplay.no_playlist", settings.Value))
+			return
+		}
+	}
+
+	if changed {
+		dp.SetAutoPlay(settings)
+		if err := saveAutoplay(i.GuildID, settings); err != nil {
+			respond(s, i, tr(i, "autoplay.save_failed"))
+			return
+		}
+	}
+
+	respond(s, i, autoplayStatus(i, settings))
+}
+
+// saveAutoplay guarda la configuración de autoplay en los ajustes del guild.
+func saveAutoplay(guildID string, settings core.AutoplaySettings) error {
+	values := map[core.SettingKey]string{
+		core.SettingAutoplay:       strconv.FormatBool(settings.Enabled),
+		core.SettingAutoplaySource: string(settings.Source),
+		core.SettingAutoplayValue:  settings.Value,
+	}
+	for key, value := range values {
+		if _, err := infra.GlobalSettings.Set(guildID, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func autoplayStatus(i *discordgo.InteractionCreate, settings core.AutoplaySettings) string {
+	source := string(settings.Source)
+	if settings.Value != "" {
+		source = fmt.Sprintf("%s (%s)", settings.Source, settings.Value)
+	}
+	return tr(i, "autoplay.status", onOff(i, settings.Enabled), source)
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+)
+
+// ChaptersCommand lista los capítulos de la canción actual y marca el que suena.
+func ChaptersCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if dp == nil {
+		respond(s, i, tr(i, "nothing_playing"))
+		return
+	}
+	song, pos := dp.Current()
+	if song == nil {
+		respond(s, i, tr(i, "nothing_playing"))
+		return
+	}
+	if len(song.Chapters) == 0 {
+		respond(s, i, tr(i, "chapters.none", song.Title))
+		return
+	}
+
+	current := song.ChapterAt(pos)
+	var sb strings.Builder
+	sb.WriteString(tr(i, "chapters.header", song.Title))
+	for idx, c := range song.Chapters {
+		marker := "  "
+		if idx == current {
+			marker = "▶"
+		}
+		sb.WriteString(fmt.Sprintf("%s %d. %s `[%s]`\n", marker, idx+1, c.Title, formatDuration(c.Start)))
+	}
+	respond(s, i, truncate(sb.String(), 2000))
+}
+
+// ChapterCommand maneja /chapter target:next|prev|<n> y salta a ese capítulo.
+func ChapterCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	song, pos := dp.Current()
+	if song == nil || len(song.Chapters) == 0 {
+		respond(s, i, tr(i, "chapter.none"))
+		return
+	}
+
+	target := strings.ToLower(i.ApplicationCommandData().Options[0].StringValue())
+	current := song.ChapterAt(pos)
+	var idx int
+	switch target {
+	case "next":
+		idx = current + 1
+	case "prev":
+		idx = current - 1
+		// Como en cualquier reproductor: si ya va avanzado, vuelve al inicio del actual
+		if current >= 0 && pos-song.Chapters[current].Start > 3*time.Second {
+			idx = current
+		}
+	default:
+		n, err := strconv.Atoi(target)
+		if err != nil {
+			respond(s, i, tr(i, "chapter.usage"))
+			return
+		}
+		idx = n - 1
+	}
+
+	if idx < 0 || idx >= len(song.Chapters) {
+		respond(s, i, tr(i, "chapter.out_of_range"))
+		return
+	}
+	c := song.Chapters[idx]
+	if !dp.Seek(c.Start) {
+		respond(s, i, tr(i, "nothing_playing"))
+		return
+	}
+	respond(s, i, tr(i, "chapter.jump", idx+1, c.Title))
+}
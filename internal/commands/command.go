@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+)
+
+// Handler ejecuta un comando (o un botón) sobre el player del guild.
+type Handler func(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate)
+
+// Policy es quién puede usar un comando.
+type Policy int
+
+const (
+	// PolicyAnyone: cualquier miembro.
+	PolicyAnyone Policy = iota
+	// PolicyRequester: quien pidió la canción actual, o un DJ.
+	PolicyRequester
+	// PolicyDJ: miembros con el rol DJ del guild.
+	PolicyDJ
+	// PolicyAdmin: sólo administradores.
+	PolicyAdmin
+)
+
+// VoiceMode es lo que un comando exige del canal de voz de quien lo usa.
+type VoiceMode int
+
+const (
+	// VoiceNone: comandos de sólo lectura. No hace falta estar en voz y
+	// reciben el player que haya en el guild, o nil; nunca se crea uno.
+	VoiceNone VoiceMode = iota
+	// VoiceAny: hay que estar en un canal de voz, cualquiera; el player se
+	// crea si no existe (p. ej. /join, que trae el bot a ese canal).
+	VoiceAny
+	// VoiceSame: como VoiceAny, pero si el bot está ocupado en otro canal
+	// hay que estar en el suyo. Para reproducir y controlar la reproducción.
+	VoiceSame
+)
+
+// Command es un comando de barra del bot: cómo se registra, quién puede
+// usarlo y qué hace. botserver lo ejecuta a través de sus middleware.
+type Command interface {
+	// Definition es el comando tal como se registra en Discord; las
+	// descripciones y traducciones las pone botserver desde el catálogo.
+	Definition() *discordgo.ApplicationCommand
+	// Policy es quién puede usar el comando tal como viene en i; puede
+	// depender del subcomando (p. ej. /playlist list frente a save).
+	Policy(i *discordgo.InteractionCreate) Policy
+	// VoiceMode indica si hay que estar en voz (y en qué canal) y, con ello,
+	// si Execute puede recibir un player nil.
+	VoiceMode() VoiceMode
+	// AttachesNowPlaying indica si el mensaje "now playing" del guild debe
+	// ir al canal donde se usa el comando (p. ej. al pedir una canción).
+	AttachesNowPlaying() bool
+	// RestoresQueue indica si el comando devuelve a la cola lo guardado en
+	// el último apagado (sólo los que piden música o llaman al bot).
+	RestoresQueue() bool
+	// Autocomplete responde a las sugerencias de las opciones con
+	// Autocomplete; los comandos sin ellas no hacen nada.
+	Autocomplete(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Execute(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate)
+}
+
+// Spec es un Command declarado con sus campos; así se definen todos los
+// comandos del bot (ver All).
+type Spec struct {
+	Def    *discordgo.ApplicationCommand
+	Access Policy
+	// SubAccess es la política de los subcomandos que no usan Access.
+	SubAccess  map[string]Policy
+	Mode       VoiceMode
+	NowPlaying bool
+	Restore    bool
+	Complete   func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	Run        Handler
+}
+
+func (c Spec) Definition() *discordgo.ApplicationCommand { return c.Def }
+func (c Spec) VoiceMode() VoiceMode                      { return c.Mode }
+func (c Spec) AttachesNowPlaying() bool                  { return c.NowPlaying }
+func (c Spec) RestoresQueue() bool                       { return c.Restore }
+
+func (c Spec) Policy(i *discordgo.InteractionCreate) Policy {
+	if i == nil || i.Type != discordgo.InteractionApplicationCommand {
+		return c.Access
+	}
+	if opts := i.ApplicationCommandData().Options; len(opts) > 0 && opts[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+		if policy, ok := c.SubAccess[opts[0].Name]; ok {
+			return policy
+		}
+	}
+	return c.Access
+}
+
+func (c Spec) Autocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if c.Complete != nil {
+		c.Complete(s, i)
+	}
+}
+
+func (c Spec) Execute(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	c.Run(dp, s, i)
+}
+
+// Lookup devuelve el comando con ese nombre.
+func Lookup(name string) (Command, bool) {
+	for _, cmd := range All {
+		if cmd.Definition().Name == name {
+			return cmd, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+// defaultBassBoost es la ganancia que aplica /filter bassboost sin valor.
+const defaultBassBoost = 8
+
+// FilterCommand maneja /filter effect:<efecto> [value].
+// Los efectos on/off (nightcore, vaporwave, 8d, karaoke, bassboost sin
+// valor) se alternan; eq, speed y pitch toman el valor indicado. El cambio
+// se aplica al momento sin perder la posición de la canción y se guarda en
+// los ajustes del guild.
+func FilterCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var effect, value string
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "effect":
+			effect = opt.StringValue()
+		case "value":
+			value = strings.TrimSpace(opt.StringValue())
+		}
+	}
+
+	f := dp.Filters()
+	switch effect {
+	case "status":
+		respond(s, i, filterStatus(i, f))
+		return
+	case "reset":
+		f = core.FilterChain{}
+	case "bassboost":
+		switch {
+		case value != "":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				respond(s, i, tr(i, "filter.bass_nan"))
+				return
+			}
+			f.BassBoost = n
+		case f.BassBoost > 0:
+			f.BassBoost = 0
+		default:
+			f.BassBoost = defaultBassBoost
+		}
+	case "nightcore":
+		f.Pitch = togglePitch(f.Pitch, core.Nightcore.Pitch)
+	case "vaporwave":
+		f.Pitch = togglePitch(f.Pitch, core.Vaporwave.Pitch)
+	case "8d":
+		f.EightD = !f.EightD
+	case "karaoke":
+		f.Karaoke = !f.Karaoke
+	case "eq":
+		if value == "" || value == "off" {
+			f.EQ = ""
+		} else {
+			f.EQ = strings.ToLower(value)
+		}
+	case "speed", "pitch":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			respond(s, i, tr(i, "filter.value_nan", effect))
+			return
+		}
+		if effect == "speed" {
+			f.Speed = v
+		} else {
+			f.Pitch = v
+		}
+	default:
+		respond(s, i, tr(i, "filter.unknown", effect))
+		return
+	}
+
+	if err := f.Validate(); err != nil {
+		respond(s, i, errorMessage(i, err))
+		return
+	}
+
+	dp.SetFilters(f)
+	if _, err := infra.GlobalSettings.Set(i.GuildID, core.SettingFilters, f.Encode()); err != nil {
+		respond(s, i, tr(i, "filter.save_failed"))
+		return
+	}
+	respond(s, i, filterStatus(i, f))
+}
+
+func filterStatus(i *discordgo.InteractionCreate, f core.FilterChain) string {
+	if f.IsZero() {
+		return tr(i, "filter.status", tr(i, "filter.none"))
+	}
+	return tr(i, "filter.status", f)
+}
+
+// togglePitch activa un preset de tono o lo quita si ya estaba puesto.
+func togglePitch(current, preset float64) float64 {
+	if current == preset {
+		return 0
+	}
+	return preset
+}
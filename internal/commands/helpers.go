@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/i18n"
+	"feints/internal/infra"
+)
+
+// respond envía una respuesta de texto simple a la interacción.
+func respond(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+		},
+	})
+}
+
+// deferResponse avisa a Discord de que la respuesta tardará (más de 3s).
+func deferResponse(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+}
+
+// editResponse reemplaza el contenido de una respuesta diferida.
+func editResponse(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_, _ = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+}
+
+// formatDuration muestra una duración como m:ss o h:mm:ss.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h, m, sec := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
+}
+
+// truncate corta un texto para que quepa en un mensaje de Discord.
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// respondEphemeral responde con un mensaje que sólo ve quien usó el comando.
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// Lang devuelve el idioma en que se responde a la interacción: el fijado en
+// los ajustes del guild o, si no se ha fijado, el del usuario o el del servidor.
+func Lang(i *discordgo.InteractionCreate) i18n.Lang {
+	return langFor(i.GuildID, i.Locale, i.GuildLocale)
+}
+
+// GuildLang devuelve el idioma de los mensajes que no responden a nadie en
+// concreto: el fijado en los ajustes o el preferido por el servidor.
+func GuildLang(guild *discordgo.Guild) i18n.Lang {
+	locale := discordgo.Locale(guild.PreferredLocale)
+	return langFor(guild.ID, "", &locale)
+}
+
+func langFor(guildID string, locale discordgo.Locale, guildLocale *discordgo.Locale) i18n.Lang {
+	settings := infra.GlobalSettings.Get(guildID)
+	var guildLang string
+	if !infra.GlobalSettings.IsDefault(guildID, core.SettingLanguage) {
+		guildLang = settings.String(core.SettingLanguage)
+	}
+	return i18n.Resolve(guildLang, locale, guildLocale, settings.String(core.SettingLanguage))
+}
+
+// onOff traduce el estado de un interruptor.
+func onOff(i *discordgo.InteractionCreate, enabled bool) string {
+	if enabled {
+		return tr(i, "on")
+	}
+	return tr(i, "off")
+}
+
+// tr traduce un mensaje al idioma de la interacción.
+func tr(i *discordgo.InteractionCreate, key string, args ...any) string {
+	return i18n.T(Lang(i), key, args...)
+}
+
+// errorMessage muestra un error al usuario en su idioma.
+func errorMessage(i *discordgo.InteractionCreate, err error) string {
+	return tr(i, "error", i18n.Message(Lang(i), err))
+}
+
+// SongErrorMessage explica en lang por qué no se pudo obtener una canción,
+// según la categoría del error de infra.
+func SongErrorMessage(lang i18n.Lang, err error) string {
+	var songErr *infra.SongError
+	switch {
+	case errors.Is(err, infra.ErrTooLong) && errors.As(err, &songErr):
+		return i18n.T(lang, "song.too_long", formatDuration(songErr.Limit))
+	case errors.Is(err, infra.ErrNotFound):
+		return i18n.T(lang, "song.not_found")
+	case errors.Is(err, infra.ErrAgeRestricted):
+		return i18n.T(lang, "song.age_restricted")
+	case errors.Is(err, infra.ErrGeoBlocked):
+		return i18n.T(lang, "song.geo_blocked")
+	case errors.Is(err, infra.ErrRateLimited):
+		return i18n.T(lang, "song.rate_limited")
+	}
+	return i18n.T(lang, "song.failed")
+}
+
+// userVoiceChannel devuelve el canal de voz de quien usa el comando, o ""
+// si no está en ninguno.
+func userVoiceChannel(s *discordgo.Session, i *discordgo.InteractionCreate) string {
+	vs, err := s.State.VoiceState(i.GuildID, i.Member.User.ID)
+	if err != nil {
+		return ""
+	}
+	return vs.ChannelID
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+)
+
+// JoinCommand trae el bot al canal de voz de quien lo usa, con la cola y la
+// canción actual intactas.
+func JoinCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	channelID := userVoiceChannel(s, i)
+	if channelID == "" {
+		respond(s, i, tr(i, "not_in_voice"))
+		return
+	}
+	if channelID == dp.VoiceChannel() {
+		respond(s, i, tr(i, "join.already", channelID))
+		return
+	}
+	if err := dp.MoveTo(channelID); err != nil {
+		respond(s, i, tr(i, "join.failed"))
+		return
+	}
+	respond(s, i, tr(i, "join.moved", channelID))
+}
@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/i18n"
+)
+
+// PlayerButtonPrefix marca los custom_id de los botones del reproductor. Lo
+// que sigue al prefijo es el comando cuya acción (y cuyos permisos) aplica.
+const PlayerButtonPrefix = "player:"
+
+// progressWidth es el número de segmentos de la barra de progreso.
+const progressWidth = 16
+
+// embedColor es el color lateral de los embeds del bot.
+const embedColor = 0x1DB954
+
+// NowPlayingCommand muestra la canción actual con sus controles.
+func NowPlayingCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	embed := NowPlayingEmbed(Lang(i), dp)
+	if embed == nil {
+		respond(s, i, tr(i, "nothing_playing"))
+		return
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:          []*discordgo.MessageEmbed{embed},
+			Components:      PlayerButtons(dp),
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
+// PlayerButton ejecuta la acción de un botón del reproductor y actualiza el
+// mensaje con el estado nuevo. action es el comando equivalente.
+func PlayerButton(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate, action string) {
+	var feedback string
+	switch action {
+	case "pause":
+		feedback = togglePause(i, dp)
+	case "skip":
+		dp.Next()
+		feedback = tr(i, "skip.done")
+	case "stop":
+		dp.Stop()
+		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Content:    tr(i, "stop.done"),
+				Embeds:     []*discordgo.MessageEmbed{},
+				Components: []discordgo.MessageComponent{},
+			},
+		})
+		return
+	case "autoplay":
+		settings := dp.AutoPlaySettings()
+		settings.Enabled = !settings.Enabled
+		dp.SetAutoPlay(settings)
+		feedback = autoplayStatus(i, settings)
+		if err := saveAutoplay(i.GuildID, settings); err != nil {
+			feedback = tr(i, "autoplay.save_failed")
+		}
+	case "shuffle":
+		dp.Shuffle()
+		feedback = tr(i, "shuffle.done")
+	default:
+		return
+	}
+
+	// Tras saltar, la siguiente canción puede no haber empezado aún: se
+	// deja el embed anterior hasta que haya algo nuevo que mostrar
+	embeds := i.Message.Embeds
+	if embed := NowPlayingEmbed(Lang(i), dp); embed != nil {
+		embeds = []*discordgo.MessageEmbed{embed}
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:         feedback,
+			Embeds:          embeds,
+			Components:      PlayerButtons(dp),
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
+
+// togglePause pausa o reanuda según el estado del player. Sin canción no
+// hace nada.
+func togglePause(i *discordgo.InteractionCreate, dp core.Player) string {
+	if song, _ := dp.Current(); song == nil {
+		return tr(i, "nothing_playing")
+	}
+	if dp.State() == "paused" {
+		dp.Resume()
+		return tr(i, "pause.resumed")
+	}
+	dp.Pause()
+	return tr(i, "pause.paused")
+}
+
+// NowPlayingEmbed describe la canción actual, o nil si no hay player o no
+// suena nada.
+func NowPlayingEmbed(lang i18n.Lang, dp core.Player) *discordgo.MessageEmbed {
+	if dp == nil {
+		return nil
+	}
+	song, pos := dp.Current()
+	if song == nil {
+		return nil
+	}
+	elapsed, length := pos-song.Start, song.Length()
+
+	embed := &discordgo.MessageEmbed{
+		Title:       truncate(song.Title, 256),
+		URL:         song.URL,
+		Color:       embedColor,
+		Description: progressBar(elapsed, length),
+		Footer:      &discordgo.MessageEmbedFooter{Text: i18n.T(lang, "state."+dp.State())},
+	}
+	if song.Thumbnail != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: song.Thumbnail}
+	}
+	if song.Uploader != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: i18n.T(lang, "np.uploader"), Value: truncate(song.Uploader, 1024), Inline: true,
+		})
+	}
+	if length > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: i18n.T(lang, "np.duration"), Value: formatDuration(length), Inline: true,
+		})
+	}
+	requester := i18n.T(lang, "np.autoplay")
+	if song.RequesterID != "" {
+		requester = "<@" + song.RequesterID + ">"
+		if !song.RequestedAt.IsZero() {
+			requester += fmt.Sprintf(" · <t:%d:R>", song.RequestedAt.Unix())
+		}
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name: i18n.T(lang, "np.requester"), Value: requester, Inline: true,
+	})
+	return embed
+}
+
+// progressBar dibuja la posición dentro de la canción: ▬▬▬🔘▬▬▬ 1:23 / 3:45.
+func progressBar(elapsed, length time.Duration) string {
+	elapsed = max(elapsed, 0)
+	if length <= 0 {
+		return "🔘" + strings.Repeat("▬", progressWidth-1) + " " + formatDuration(elapsed)
+	}
+	knob := min(int(float64(elapsed)/float64(length)*progressWidth), progressWidth-1)
+	return fmt.Sprintf("%s🔘%s `%s / %s`",
+		strings.Repeat("▬", knob), strings.Repeat("▬", progressWidth-1-knob),
+		formatDuration(min(elapsed, length)), formatDuration(length))
+}
+
+// PlayerButtons son los controles ⏯ ⏭ ⏹ 🔁 🔀 del mensaje "now playing".
+func PlayerButtons(dp core.Player) []discordgo.MessageComponent {
+	button := func(action, emoji string, style discordgo.ButtonStyle) discordgo.MessageComponent {
+		return discordgo.Button{
+			CustomID: PlayerButtonPrefix + action,
+			Emoji:    &discordgo.ComponentEmoji{Name: emoji},
+			Style:    style,
+		}
+	}
+	pauseStyle := discordgo.PrimaryButton
+	if dp.State() == "paused" {
+		pauseStyle = discordgo.SuccessButton
+	}
+	autoplayStyle := discordgo.SecondaryButton
+	if dp.AutoPlaySettings().Enabled {
+		autoplayStyle = discordgo.SuccessButton
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			button("pause", "⏯", pauseStyle),
+			button("skip", "⏭", discordgo.SecondaryButton),
+			button("stop", "⏹", discordgo.DangerButton),
+			button("autoplay", "🔁", autoplayStyle),
+			button("shuffle", "🔀", discordgo.SecondaryButton),
+		}},
+	}
+}
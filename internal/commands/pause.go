@@ -1 +1,12 @@
-package commands
\ No newline at end of file
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+)
+
+// PauseCommand pausa la reproducción o la reanuda si ya estaba en pausa.
+func PauseCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	respond(s, i, togglePause(i, dp))
+}
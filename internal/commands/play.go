@@ -2,11 +2,12 @@
 package commands
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"feints/internal/core"
+	"feints/internal/infra"
 )
 
 // PlayCommand reproduce o añade una canción a la cola
@@ -17,48 +18,144 @@ func PlayCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionC
 		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: "❌ No se proporcionó ninguna canción.",
+				Content: tr(i, "play.no_song"),
 			},
 		})
 		return
 	}
 
-	query := options[0].StringValue()
+	var query string
+	var split bool
+	for _, opt := range options {
+		switch opt.Name {
+		case "search":
+			query = opt.StringValue()
+		case "split_chapters":
+			split = opt.BoolValue()
+		}
+	}
 	if query == "" {
 		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: "❌ La búsqueda no puede estar vacía.",
+				Content: tr(i, "play.empty_query"),
 			},
 		})
 		return
 	}
 
+	settings := infra.GlobalSettings.Get(i.GuildID)
+	maxQueue := settings.Int(core.SettingMaxQueueLength)
+	if len(dp.ListQueue()) >= maxQueue {
+		respond(s, i, tr(i, "play.queue_full", maxQueue))
+		return
+	}
+
+	if split {
+		playChapters(dp, s, i, query, maxQueue)
+		return
+	}
+
+	song := core.Song{URL: query}
+	reply := func(content string) { respond(s, i, content) }
+	// Con límite de duración por usuario hay que saber cuánto dura antes
+	// de encolarla; yt-dlp puede tardar más de los 3s que da Discord
+	if settings.Duration(core.SettingMaxUserDuration) > 0 {
+		deferResponse(s, i)
+		reply = func(content string) { editResponse(s, i, content) }
+		meta, err := infra.Metadata(query)
+		if err != nil {
+			reply(tr(i, "error", SongErrorMessage(Lang(i), err)))
+			return
+		}
+		song = *meta
+	}
+	setRequester(&song, i)
+	if reason := userLimit(i, dp.ListQueue(), []core.Song{song}); reason != "" {
+		reply(reason)
+		return
+	}
+
 	// Añadir canción a la cola
-	dp.AddSong(core.Song{
-		URL:   query,
-	})
+	dp.AddSong(song)
 	dp.Play()
-	
-	
-
-	// Responder al usuario
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("🎶 Añadido a la cola: **%s**", query),
-		},
-	})
+
+	title := song.Title
+	if title == "" {
+		title = query
+	}
+	reply(tr(i, "play.added", title))
+}
+
+// setRequester apunta en la canción quién la pide y cuándo.
+func setRequester(song *core.Song, i *discordgo.InteractionCreate) {
+	song.RequesterID = i.Member.User.ID
+	song.RequesterName = i.Member.DisplayName()
+	song.RequestedAt = time.Now()
+}
+
+// userLimit comprueba los límites por usuario del guild (canciones y
+// duración en cola) antes de añadir songs. Devuelve el motivo del rechazo,
+// o "" si caben.
+func userLimit(i *discordgo.InteractionCreate, queue []*core.Song, songs []core.Song) string {
+	settings := infra.GlobalSettings.Get(i.GuildID)
+	maxSongs := settings.Int(core.SettingMaxUserSongs)
+	maxDuration := settings.Duration(core.SettingMaxUserDuration)
+
+	var count int
+	var total time.Duration
+	for _, song := range queue {
+		if song.RequesterID == i.Member.User.ID {
+			count++
+			total += song.Length()
+		}
+	}
+	if maxSongs > 0 && count+len(songs) > maxSongs {
+		return tr(i, "play.user_songs", count, maxSongs)
+	}
+	for _, song := range songs {
+		total += song.Length()
+	}
+	if maxDuration > 0 && total > maxDuration {
+		return tr(i, "play.user_duration", formatDuration(maxDuration))
+	}
+	return ""
 }
 
+// playChapters encola cada capítulo de un vídeo largo como una pista
+// virtual. Todas comparten el mismo fichero, que se descarga una sola vez.
+func playChapters(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate, url string, maxQueue int) {
+	// yt-dlp puede tardar más de los 3s que da Discord para responder
+	deferResponse(s, i)
+
+	meta, err := infra.Metadata(url)
+	if err != nil {
+		editResponse(s, i, tr(i, "error", SongErrorMessage(Lang(i), err)))
+		return
+	}
+	if len(meta.Chapters) == 0 {
+		editResponse(s, i, tr(i, "play.no_chapters", meta.Title))
+		return
+	}
+
+	if len(dp.ListQueue())+len(meta.Chapters) > maxQueue {
+		editResponse(s, i, tr(i, "play.chapters_too_many", len(meta.Chapters), maxQueue))
+		return
+	}
+
+	setRequester(meta, i)
+	clips := make([]core.Song, len(meta.Chapters))
+	for idx, c := range meta.Chapters {
+		clips[idx] = meta.Clip(c)
+	}
+	if reason := userLimit(i, dp.ListQueue(), clips); reason != "" {
+		editResponse(s, i, reason)
+		return
+	}
+	for _, clip := range clips {
+		dp.AddSong(clip)
+	}
+	dp.Play()
 
-func AutoPlay(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
-	dp.AutoPlay()
-	// Responder al usuario
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("Autoplay activado"),
-		},
-	})
+	editResponse(s, i, tr(i, "play.chapters_added", len(meta.Chapters), meta.Title))
 }
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+// PlaylistCommand maneja /playlist save|list. Las playlists guardadas son
+// las que usa el autoplay con la fuente "playlist".
+func PlaylistCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	switch sub.Name {
+	case "save":
+		var title string
+		for _, opt := range sub.Options {
+			if opt.Name == "title" {
+				title = strings.TrimSpace(opt.StringValue())
+			}
+		}
+		songs := playlistSongs(dp)
+		if len(songs) == 0 {
+			respond(s, i, tr(i, "playlist.empty"))
+			return
+		}
+		if title == "" {
+			respond(s, i, tr(i, "playlist.no_title"))
+			return
+		}
+		if err := infra.SavePlaylist(i.GuildID, title, songs); err != nil {
+			respond(s, i, tr(i, "playlist.save_failed"))
+			return
+		}
+		respond(s, i, tr(i, "playlist.saved", title, len(songs)))
+
+	case "list":
+		names, err := infra.ListPlaylists(i.GuildID)
+		if err != nil {
+			respond(s, i, tr(i, "playlist.list_failed"))
+			return
+		}
+		if len(names) == 0 {
+			respond(s, i, tr(i, "playlist.none"))
+			return
+		}
+		respond(s, i, tr(i, "playlist.list", strings.Join(names, ", ")))
+	}
+}
+
+// playlistSongs devuelve la canción actual y la cola, sin quién las pidió:
+// en una playlist las pone el autoplay.
+func playlistSongs(dp core.Player) []core.Song {
+	if dp == nil {
+		return nil
+	}
+	var songs []core.Song
+	if current, _ := dp.Current(); current != nil {
+		songs = append(songs, *current)
+	}
+	for _, song := range dp.ListQueue() {
+		songs = append(songs, *song)
+	}
+	for idx := range songs {
+		songs[idx].RequesterID, songs[idx].RequesterName = "", ""
+		songs[idx].RequestedAt = time.Time{}
+	}
+	return songs
+}
@@ -2,34 +2,148 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
 	"feints/internal/core"
 )
 
+// QueuePageButtonPrefix marca los custom_id de los botones ◀ ▶ de /queue;
+// le sigue el número de página a mostrar.
+const QueuePageButtonPrefix = "queue:"
+
+// queuePageSize es cuántas canciones se muestran por página.
+const queuePageSize = 10
+
+// QueueCommand muestra la primera página de la cola.
 func QueueCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
-	queue := dp.ListQueue()
-	if len(queue) == 0 {
-		_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "📭 La cola está vacía.",
-			},
-		})
+	embed, components := queuePage(i, dp, 0)
+	if embed == nil {
+		respond(s, i, tr(i, "queue.empty"))
 		return
 	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:          []*discordgo.MessageEmbed{embed},
+			Components:      components,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		},
+	})
+}
 
+// QueuePageButton cambia la página que muestra un mensaje de /queue.
+func QueuePageButton(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate, page string) {
+	n, err := strconv.Atoi(page)
+	if err != nil {
+		return
+	}
+	data := &discordgo.InteractionResponseData{AllowedMentions: &discordgo.MessageAllowedMentions{}}
+	embed, components := queuePage(i, dp, n)
+	if embed == nil {
+		data.Content = tr(i, "queue.empty")
+		data.Embeds = []*discordgo.MessageEmbed{}
+		data.Components = []discordgo.MessageComponent{}
+	} else {
+		data.Embeds = []*discordgo.MessageEmbed{embed}
+		data.Components = components
+	}
+	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	})
+}
+
+// queuePage construye la página pedida (ajustada a las que haya) con la
+// canción actual arriba. Devuelve nil si no hay player o no suena ni hay
+// nada en cola.
+func queuePage(i *discordgo.InteractionCreate, dp core.Player, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	if dp == nil {
+		return nil, nil
+	}
+	queue := dp.ListQueue()
+	current, pos := dp.Current()
+	if current == nil && len(queue) == 0 {
+		return nil, nil
+	}
+
+	pages := max((len(queue)+queuePageSize-1)/queuePageSize, 1)
+	page = min(max(page, 0), pages-1)
+
+	// Tiempo restante: lo que queda de la actual más la cola. Las canciones
+	// que aún no se han resuelto no tienen duración y no suman.
+	var remaining time.Duration
+	unknown := false
 	var sb strings.Builder
+	if current != nil {
+		elapsed, length := max(pos-current.Start, 0), current.Length()
+		remaining += max(length-elapsed, 0)
+		sb.WriteString(tr(i, "queue.now_playing", queueEntry(i, current),
+			formatDuration(elapsed), formatDuration(length)))
+		sb.WriteString("\n\n")
+	}
 	for idx, song := range queue {
-		sb.WriteString(fmt.Sprintf("%d. %s\n", idx+1, song.Title))
+		remaining += song.Length()
+		if song.Length() == 0 {
+			unknown = true
+		}
+		if idx < page*queuePageSize || idx >= (page+1)*queuePageSize {
+			continue
+		}
+		length := "?"
+		if song.Length() > 0 {
+			length = formatDuration(song.Length())
+		}
+		sb.WriteString(fmt.Sprintf("`%d.` %s `%s`\n", idx+1, queueEntry(i, song), length))
 	}
 
-	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("🎶 Cola actual:\n%s", sb.String()),
+	total := formatDuration(remaining)
+	if unknown {
+		total += "+"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       tr(i, "queue.title"),
+		Color:       embedColor,
+		Description: truncate(sb.String(), 4096),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: tr(i, "queue.footer", page+1, pages, len(queue), total),
 		},
-	})
+	}
+	if pages == 1 {
+		return embed, nil
+	}
+
+	button := func(target int, emoji string, disabled bool) discordgo.MessageComponent {
+		return discordgo.Button{
+			CustomID: QueuePageButtonPrefix + strconv.Itoa(target),
+			Emoji:    &discordgo.ComponentEmoji{Name: emoji},
+			Style:    discordgo.SecondaryButton,
+			Disabled: disabled,
+		}
+	}
+	return embed, []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			button(page-1, "◀", page == 0),
+			button(page+1, "▶", page == pages-1),
+		}},
+	}
+}
+
+// queueEntry muestra una canción como enlace con quien la pidió.
+func queueEntry(i *discordgo.InteractionCreate, song *core.Song) string {
+	title := song.Title
+	if title == "" {
+		title = song.URL
+	}
+	entry := truncate(title, 80)
+	if strings.HasPrefix(song.URL, "http") {
+		entry = fmt.Sprintf("[%s](%s)", entry, song.URL)
+	}
+	if song.RequesterID != "" {
+		return entry + " · <@" + song.RequesterID + ">"
+	}
+	return entry + " · " + tr(i, "np.autoplay")
 }
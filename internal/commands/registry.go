@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+)
+
+// All son los comandos del bot. Añadir uno es añadir aquí su Spec: el
+// registro en Discord, los permisos y el despacho salen de esta lista.
+var All = []Command{
+	Spec{
+		Def: &discordgo.ApplicationCommand{
+			Name: "play",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionString,
+					Name:         "search",
+					Required:     true,
+					Autocomplete: true,
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionBoolean,
+					Name: "split_chapters",
+				},
+			},
+		},
+		Mode:       VoiceSame,
+		NowPlaying: true,
+		Restore:    true,
+		Complete:   SearchCommand,
+		Run:        PlayCommand,
+	},
+	Spec{
+		Def:    &discordgo.ApplicationCommand{Name: "stop"},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    StopCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{Name: "queue"},
+		Run: QueueCommand,
+	},
+	Spec{
+		Def:    &discordgo.ApplicationCommand{Name: "skip"},
+		Access: PolicyRequester,
+		Mode:   VoiceSame,
+		Run:    SkipCommand,
+	},
+	Spec{
+		Def:    &discordgo.ApplicationCommand{Name: "clear"},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    ClearCommand,
+	},
+	Spec{
+		Def:    &discordgo.ApplicationCommand{Name: "pause"},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    PauseCommand,
+	},
+	Spec{
+		Def:    &discordgo.ApplicationCommand{Name: "shuffle"},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    ShuffleCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{Name: "status"},
+		Run: StatusCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{Name: "nowplaying"},
+		Run: NowPlayingCommand,
+	},
+	Spec{
+		Def:     &discordgo.ApplicationCommand{Name: "join"},
+		Access:  PolicyDJ,
+		Mode:    VoiceAny,
+		Restore: true,
+		Run:     JoinCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{
+			Name: "autoplay",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "mode",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "on", Value: "on"},
+						{Name: "off", Value: "off"},
+						{Name: "status", Value: "status"},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "source",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: string(core.AutoplaySmart), Value: string(core.AutoplaySmart)},
+						{Name: string(core.AutoplayLocal), Value: string(core.AutoplayLocal)},
+						{Name: string(core.AutoplayPlaylist), Value: string(core.AutoplayPlaylist)},
+						{Name: string(core.AutoplayRelated), Value: string(core.AutoplayRelated)},
+						{Name: string(core.AutoplayGenre), Value: string(core.AutoplayGenre)},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "value",
+				},
+			},
+		},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    AutoPlay,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{
+			Name: "filter",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:     discordgo.ApplicationCommandOptionString,
+					Name:     "effect",
+					Required: true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "bass boost", Value: "bassboost"},
+						{Name: "nightcore", Value: "nightcore"},
+						{Name: "vaporwave", Value: "vaporwave"},
+						{Name: "8D", Value: "8d"},
+						{Name: "karaoke", Value: "karaoke"},
+						{Name: "eq", Value: "eq"},
+						{Name: "speed", Value: "speed"},
+						{Name: "pitch", Value: "pitch"},
+						{Name: "reset", Value: "reset"},
+						{Name: "status", Value: "status"},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "value",
+				},
+			},
+		},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    FilterCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{
+			Name: "sponsorblock",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "mode",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "on", Value: "on"},
+						{Name: "off", Value: "off"},
+						{Name: "status", Value: "status"},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionString,
+					Name: "categories",
+				},
+			},
+		},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    SponsorBlockCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{Name: "chapters"},
+		Run: ChaptersCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{
+			Name: "chapter",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:     discordgo.ApplicationCommandOptionString,
+					Name:     "target",
+					Required: true,
+				},
+			},
+		},
+		Access: PolicyDJ,
+		Mode:   VoiceSame,
+		Run:    ChapterCommand,
+	},
+	Spec{
+		Def: &discordgo.ApplicationCommand{
+			Name: "playlist",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "save",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:      discordgo.ApplicationCommandOptionString,
+							Name:      "title",
+							Required:  true,
+							MaxLength: 50,
+						},
+					},
+				},
+				{
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Name: "list",
+				},
+			},
+		},
+		// Listar es de sólo lectura; guardar, de DJ
+		Access:    PolicyDJ,
+		SubAccess: map[string]Policy{"list": PolicyAnyone},
+		Run:       PlaylistCommand,
+	},
+	Spec{
+		Def:    settingsDefinition(),
+		Access: PolicyAdmin,
+		Run:    SettingsCommand,
+	},
+}
+
+// settingsDefinition define /settings; las claves salen de core.SettingDefs.
+func settingsDefinition() *discordgo.ApplicationCommand {
+	adminOnly := int64(discordgo.PermissionAdministrator)
+	var keys []*discordgo.ApplicationCommandOptionChoice
+	for _, def := range core.SettingDefs {
+		keys = append(keys, &discordgo.ApplicationCommandOptionChoice{Name: string(def.Key), Value: string(def.Key)})
+	}
+	keyOption := func(required bool) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:     discordgo.ApplicationCommandOptionString,
+			Name:     "key",
+			Required: required,
+			Choices:  keys,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "settings",
+		DefaultMemberPermissions: &adminOnly,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:    discordgo.ApplicationCommandOptionSubCommand,
+				Name:    "get",
+				Options: []*discordgo.ApplicationCommandOption{keyOption(false)},
+			},
+			{
+				Type: discordgo.ApplicationCommandOptionSubCommand,
+				Name: "set",
+				Options: []*discordgo.ApplicationCommandOption{
+					keyOption(true),
+					{
+						Type:     discordgo.ApplicationCommandOptionString,
+						Name:     "value",
+						Required: true,
+					},
+				},
+			},
+			{
+				Type:    discordgo.ApplicationCommandOptionSubCommand,
+				Name:    "reset",
+				Options: []*discordgo.ApplicationCommandOption{keyOption(false)},
+			},
+		},
+	}
+}
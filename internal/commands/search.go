@@ -6,12 +6,18 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 
+	"feints/internal/core"
 	"feints/internal/infra"
 )
 
 // SearchCommand maneja el autocompletado de /play search
 func SearchCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	query := i.ApplicationCommandData().Options[0].StringValue()
+	var query string
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "search" {
+			query = opt.StringValue()
+		}
+	}
 	log.Println("[SearchCommand] Query recibida:", query)
 	if query == "" {
 		log.Println("[SearchCommand] Query vacía, saliendo")
@@ -26,11 +32,16 @@ func SearchCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 	log.Println("[SearchCommand] yt-dlp ejecutado correctamente")
 
+	// No ofrecer lo que /play rechazará por largo
+	maxDuration := infra.GlobalSettings.Get(i.GuildID).Duration(core.SettingMaxSongDuration)
 	var choices []*discordgo.ApplicationCommandOptionChoice
-	for idx, video := range results {
-		if idx >= 25 {
+	for _, video := range results {
+		if len(choices) >= 25 {
 			break
 		}
+		if video.Duration > maxDuration {
+			continue
+		}
 
 		name := fmt.Sprintf("[%s] %s", video.Duration, video.Title)
 		if len(name) > 100 {
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+// SettingsCommand maneja /settings get|set|reset. Sólo para administradores
+// (ver la política del comando en botserver).
+func SettingsCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	var key core.SettingKey
+	var value string
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "key":
+			key = core.SettingKey(opt.StringValue())
+		case "value":
+			value = opt.StringValue()
+		}
+	}
+
+	switch sub.Name {
+	case "get":
+		respondEphemeral(s, i, settingsList(i, key))
+
+	case "set":
+		canonical, err := infra.GlobalSettings.Set(i.GuildID, key, value)
+		if err != nil {
+			respondEphemeral(s, i, errorMessage(i, err))
+			return
+		}
+		applySetting(dp, i.GuildID, key)
+		respondEphemeral(s, i, tr(i, "settings.updated", key, displaySetting(key, canonical)))
+
+	case "reset":
+		if err := infra.GlobalSettings.Reset(i.GuildID, key); err != nil {
+			respondEphemeral(s, i, tr(i, "settings.reset_failed"))
+			return
+		}
+		applySetting(dp, i.GuildID, key)
+		if key == "" {
+			respondEphemeral(s, i, tr(i, "settings.reset_all"))
+			return
+		}
+		respondEphemeral(s, i, tr(i, "settings.reset_one", key))
+	}
+}
+
+// applySetting aplica al player los ajustes que tienen efecto inmediato.
+func applySetting(dp core.Player, guildID string, key core.SettingKey) {
+	if dp == nil {
+		// Sin player: el próximo que se cree ya los leerá
+		return
+	}
+	settings := infra.GlobalSettings.Get(guildID)
+	switch key {
+	case core.SettingAutoplay, core.SettingAutoplaySource, core.SettingAutoplayValue:
+		dp.SetAutoPlay(settings.Autoplay())
+	case core.SettingQueueStrategy:
+		dp.SetQueueStrategy(core.LookupQueueStrategy(settings.String(core.SettingQueueStrategy)))
+	case core.SettingFilters:
+		dp.SetFilters(settings.Filters())
+	case "":
+		dp.SetAutoPlay(settings.Autoplay())
+		dp.SetQueueStrategy(core.LookupQueueStrategy(settings.String(core.SettingQueueStrategy)))
+		dp.SetFilters(settings.Filters())
+	}
+}
+
+// settingsList muestra un ajuste o, sin clave, todos.
+func settingsList(i *discordgo.InteractionCreate, key core.SettingKey) string {
+	settings := infra.GlobalSettings.Get(i.GuildID)
+	var sb strings.Builder
+	sb.WriteString(tr(i, "settings.header"))
+	for _, def := range core.SettingDefs {
+		if key != "" && def.Key != key {
+			continue
+		}
+		suffix := ""
+		if infra.GlobalSettings.IsDefault(i.GuildID, def.Key) {
+			suffix = tr(i, "default_suffix")
+		}
+		sb.WriteString(fmt.Sprintf("• **%s** = %s%s — %s\n",
+			def.Key, displaySetting(def.Key, settings.String(def.Key)), suffix, tr(i, "setting."+string(def.Key))))
+	}
+	return truncate(sb.String(), 2000)
+}
+
+func displaySetting(key core.SettingKey, value string) string {
+	if value == "" {
+		return "—"
+	}
+	def, _ := core.LookupSetting(string(key))
+	switch def.Type {
+	case core.TypeRole:
+		return "<@&" + value + ">"
+	case core.TypeChannel:
+		return "<#" + value + ">"
+	case core.TypeFilters:
+		f, _ := core.ParseFilterChain(value)
+		return f.String()
+	}
+	return value
+}
@@ -0,0 +1,17 @@
+package commands
+
+import (
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+)
+
+// ShuffleCommand mezcla las canciones pendientes de la cola.
+func ShuffleCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if len(dp.ListQueue()) == 0 {
+		respond(s, i, tr(i, "queue.empty"))
+		return
+	}
+	dp.Shuffle()
+	respond(s, i, tr(i, "shuffle.done"))
+}
@@ -8,12 +8,26 @@ import (
 
 func SkipCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	dp.Next()
-	
 
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: "⏭ Ahora reproduciendo ",
+			Content: tr(i, "skip.done"),
 		},
 	})
 }
+
+// VoteSkipCommand registra el voto de un usuario sin permiso para saltar
+// directamente. Al alcanzar required votos se salta la canción.
+func VoteSkipCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate, required int) {
+	if song, _ := dp.Current(); song == nil {
+		respondEphemeral(s, i, tr(i, "nothing_playing"))
+		return
+	}
+	votes, skipped := dp.VoteSkip(i.Member.User.ID, required)
+	if skipped {
+		respond(s, i, tr(i, "vote.passed", votes, required))
+		return
+	}
+	respond(s, i, tr(i, "vote.registered", votes, required))
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"feints/internal/core"
+	"feints/internal/infra"
+)
+
+// SponsorBlockCommand maneja /sponsorblock [mode:on|off|status] [categories].
+// Configura por guild qué segmentos (intros, partes no musicales...) se saltan.
+func SponsorBlockCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	for _, opt := range i.ApplicationCommandData().Options {
+		var key core.SettingKey
+		switch opt.Name {
+		case "mode":
+			if opt.StringValue() == "status" {
+				continue
+			}
+			key = core.SettingSponsorBlock
+		case "categories":
+			key = core.SettingSponsorBlockCat
+		default:
+			continue
+		}
+		if _, err := infra.GlobalSettings.Set(i.GuildID, key, opt.StringValue()); err != nil {
+			respond(s, i, errorMessage(i, err))
+			return
+		}
+	}
+
+	settings := infra.GlobalSettings.Get(i.GuildID).Segments()
+	categories := settings.Categories
+	if len(categories) == 0 {
+		categories = core.DefaultSegmentCategories
+	}
+	respond(s, i, tr(i, "sponsorblock.status", onOff(i, settings.Enabled), strings.Join(categories, ", ")))
+}
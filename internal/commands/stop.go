@@ -12,7 +12,7 @@ func StopCommand(dp core.Player, s *discordgo.Session, i *discordgo.InteractionC
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: "⏹ Reproducción detenida y cola limpiada.",
+			Content: tr(i, "stop.done"),
 		},
 	})
 }
@@ -23,7 +23,7 @@ func ClearCommand(dp core.Player, s *discordgo.Session, i *discordgo.Interaction
 	_ = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
-			Content: "⏹ cola limpiada.",
+			Content: tr(i, "clear.done"),
 		},
 	})
 }
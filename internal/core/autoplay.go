@@ -0,0 +1,39 @@
+package core
+
+// AutoplaySource indica de dónde saca canciones el autoplay.
+type AutoplaySource string
+
+const (
+	// AutoplaySmart mezcla canciones relacionadas y de la biblioteca local.
+	AutoplaySmart AutoplaySource = "smart"
+	// AutoplayLocal elige al azar de la biblioteca local.
+	AutoplayLocal AutoplaySource = "local"
+	// AutoplayPlaylist reproduce una playlist guardada (Value es su nombre).
+	AutoplayPlaylist AutoplaySource = "playlist"
+	// AutoplayRelated sólo usa canciones relacionadas con la última reproducida.
+	AutoplayRelated AutoplaySource = "related"
+	// AutoplayGenre elige de la biblioteca local por género (Value es el género).
+	AutoplayGenre AutoplaySource = "genre"
+)
+
+// AutoplaySources lista las fuentes válidas en el orden en que se muestran.
+var AutoplaySources = []AutoplaySource{
+	AutoplaySmart, AutoplayLocal, AutoplayPlaylist, AutoplayRelated, AutoplayGenre,
+}
+
+// Valid indica si la fuente es una de las conocidas.
+func (s AutoplaySource) Valid() bool {
+	for _, src := range AutoplaySources {
+		if s == src {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoplaySettings es la configuración de autoplay de un guild.
+type AutoplaySettings struct {
+	Enabled bool           `json:"enabled"`
+	Source  AutoplaySource `json:"source"`
+	Value   string         `json:"value"`
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"feints/internal/i18n"
+)
+
+// Límites de los filtros ajustables.
+const (
+	MinSpeed     = 0.5
+	MaxSpeed     = 2.0
+	MaxBassBoost = 20 // dB
+)
+
+// EQPresets son los presets de ecualizador disponibles.
+var EQPresets = []string{"pop", "rock", "jazz", "classical", "electronic", "vocal"}
+
+// FilterChain describe los efectos de audio activos en un player.
+// El valor cero equivale a no aplicar ningún filtro. Se guarda por guild
+// en el ajuste SettingFilters (ver Encode).
+type FilterChain struct {
+	EQ        string  `json:"eq,omitempty"`         // preset de EQPresets, "" = plano
+	BassBoost int     `json:"bass_boost,omitempty"` // ganancia en dB
+	Speed     float64 `json:"speed,omitempty"`      // 1 = normal; cambia tempo sin tocar el tono
+	Pitch     float64 `json:"pitch,omitempty"`      // 1 = normal; cambia tono y velocidad juntos
+	EightD    bool    `json:"eight_d,omitempty"`    // paneo circular
+	Karaoke   bool    `json:"karaoke,omitempty"`    // elimina la voz centrada
+}
+
+// ParseFilterChain lee unos filtros guardados con Encode ("" = ninguno).
+func ParseFilterChain(raw string) (FilterChain, error) {
+	var f FilterChain
+	if raw == "" {
+		return f, nil
+	}
+	err := json.Unmarshal([]byte(raw), &f)
+	return f, err
+}
+
+// Encode devuelve los filtros como se guardan en los ajustes: en JSON, o ""
+// si no hay ninguno.
+func (f FilterChain) Encode() string {
+	if f.IsZero() {
+		return ""
+	}
+	data, _ := json.Marshal(f)
+	return string(data)
+}
+
+// Nightcore y Vaporwave son atajos sobre Pitch.
+var (
+	Nightcore = FilterChain{Pitch: 1.25}
+	Vaporwave = FilterChain{Pitch: 0.8}
+)
+
+// Tempo devuelve cuánto avanza la canción por cada segundo reproducido.
+func (f FilterChain) Tempo() float64 {
+	return orOne(f.Speed) * orOne(f.Pitch)
+}
+
+// IsZero indica si no hay ningún filtro activo.
+func (f FilterChain) IsZero() bool {
+	return f.EQ == "" && f.BassBoost == 0 && orOne(f.Speed) == 1 && orOne(f.Pitch) == 1 &&
+		!f.EightD && !f.Karaoke
+}
+
+// Validate comprueba que los valores estén dentro de los límites.
+func (f FilterChain) Validate() error {
+	if f.EQ != "" && !containsFold(EQPresets, f.EQ) {
+		return i18n.Errorf("filter.unknown_eq", f.EQ)
+	}
+	if f.BassBoost < 0 || f.BassBoost > MaxBassBoost {
+		return i18n.Errorf("filter.bass_range", MaxBassBoost)
+	}
+	for _, v := range []float64{f.Speed, f.Pitch} {
+		if v != 0 && (v < MinSpeed || v > MaxSpeed) {
+			return i18n.Errorf("filter.speed_range", MinSpeed, MaxSpeed)
+		}
+	}
+	return nil
+}
+
+// String devuelve una descripción corta de los filtros activos.
+func (f FilterChain) String() string {
+	if f.IsZero() {
+		return "ninguno"
+	}
+	var parts []string
+	if f.EQ != "" {
+		parts = append(parts, "eq "+f.EQ)
+	}
+	if f.BassBoost > 0 {
+		parts = append(parts, fmt.Sprintf("bass +%ddB", f.BassBoost))
+	}
+	if s := orOne(f.Speed); s != 1 {
+		parts = append(parts, fmt.Sprintf("speed x%.2f", s))
+	}
+	if p := orOne(f.Pitch); p != 1 {
+		parts = append(parts, fmt.Sprintf("pitch x%.2f", p))
+	}
+	if f.EightD {
+		parts = append(parts, "8d")
+	}
+	if f.Karaoke {
+		parts = append(parts, "karaoke")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orOne(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
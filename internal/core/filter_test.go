@@ -0,0 +1,45 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"feints/internal/i18n"
+)
+
+func TestFilterSetting(t *testing.T) {
+	def, _ := LookupSetting(string(SettingFilters))
+	tests := []struct {
+		name    string
+		raw     string
+		want    string // forma guardada
+		wantErr string // clave de i18n
+	}{
+		{name: "none", raw: "", want: ""},
+		{name: "zero", raw: `{"speed": 1, "pitch": 0}`, want: ""},
+		{name: "chain", raw: Nightcore.Encode(), want: `{"pitch":1.25}`},
+		{name: "canonical", raw: ` {"karaoke": true, "eq": "rock", "bass_boost": 6} `, want: `{"eq":"rock","bass_boost":6,"karaoke":true}`},
+		{name: "not json", raw: "nightcore", wantErr: "settings.not_filters"},
+		{name: "out of range", raw: `{"speed": 3}`, wantErr: "filter.speed_range"},
+		{name: "unknown eq", raw: `{"eq": "metal"}`, wantErr: "filter.unknown_eq"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := def.Normalize(tt.raw)
+			if tt.wantErr != "" {
+				var msg *i18n.Error
+				if !errors.As(err, &msg) || msg.Key != tt.wantErr {
+					t.Fatalf("Normalize(%q) error = %v, want %s", tt.raw, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("Normalize(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+			}
+			f := Settings{SettingFilters: got}.Filters()
+			if f.Encode() != got {
+				t.Errorf("Filters().Encode() = %q, want %q", f.Encode(), got)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 package core
 
+import "time"
 
 // --- Player interface ---
 // Esto es lo que vas a usar en tu bot sin importar la implementación
@@ -11,6 +12,34 @@ type Player interface {
 	Resume()
 	Stop()
 	ListQueue() []*Song
+	// Shuffle mezcla las canciones pendientes de la cola.
+	Shuffle()
+	// SetQueueStrategy cambia el orden en que suenan las canciones pendientes.
+	SetQueueStrategy(strategy QueueStrategy)
 	State() string
-	AutoPlay()
+	// VoiceChannel es el canal de voz en el que suena (o sonará) el player.
+	VoiceChannel() string
+	// MoveTo lleva el player a otro canal de voz del guild sin tocar la cola
+	// ni la canción actual. Si no está conectado, se unirá al nuevo canal con
+	// la próxima canción.
+	MoveTo(channelID string) error
+	Current() (*Song, time.Duration)
+	Seek(pos time.Duration) bool
+	// VoteSkip registra el voto de un usuario para saltar la canción actual y
+	// la salta si se alcanzan los votos necesarios. Los votos se reinician al
+	// cambiar de canción.
+	VoteSkip(userID string, required int) (votes int, skipped bool)
+	SetAutoPlay(settings AutoplaySettings)
+	AutoPlaySettings() AutoplaySettings
+	SetFilters(filters FilterChain)
+	Filters() FilterChain
+	// OnVoiceFailure registra una función a la que avisar cuando no se
+	// pueda entrar en el canal de voz o recuperar la conexión.
+	OnVoiceFailure(fn func(err error))
+	// OnSongFailure registra una función a la que avisar cuando una canción
+	// no se pueda obtener (ver los errores de infra) y se salte.
+	OnSongFailure(fn func(song Song, err error))
+	// Close para el player, suelta la conexión de voz y termina sus
+	// goroutines. Tras cerrarlo las órdenes se ignoran.
+	Close()
 }
@@ -0,0 +1,92 @@
+package core
+
+import "slices"
+
+// QueueStrategy decide en qué orden suenan las canciones pendientes. La
+// cola se guarda en orden de llegada y la estrategia calcula el orden
+// efectivo, así que se puede cambiar en cualquier momento.
+type QueueStrategy interface {
+	// Name identifica la estrategia en los ajustes.
+	Name() string
+	// Order devuelve la cola en el orden en que sonará, sin modificarla.
+	// turns dice a quién le tocó antes, para las estrategias que reparten
+	// turnos.
+	Order(queue []*Song, turns Turns) []*Song
+}
+
+// Turns recuerda a quién le tocó por última vez, para que el reparto de
+// turnos siga la ronda entre una canción y la siguiente aunque la que acaba
+// de sonar ya no esté en la cola. El valor cero sirve (no ha sonado nadie).
+type Turns struct {
+	seq  int
+	last map[string]int // RequesterID -> seq de su última canción
+}
+
+// Served anota que empieza a sonar una canción de requesterID.
+func (t *Turns) Served(requesterID string) {
+	if t.last == nil {
+		t.last = make(map[string]int)
+	}
+	t.seq++
+	t.last[requesterID] = t.seq
+}
+
+var (
+	// FIFO reproduce en orden de llegada.
+	FIFO QueueStrategy = fifo{}
+	// RoundRobin reparte turnos entre quienes piden canciones.
+	RoundRobin QueueStrategy = roundRobin{}
+)
+
+// QueueStrategies son las estrategias disponibles; la primera es la de por defecto.
+var QueueStrategies = []QueueStrategy{FIFO, RoundRobin}
+
+// LookupQueueStrategy devuelve la estrategia con ese nombre, o FIFO.
+func LookupQueueStrategy(name string) QueueStrategy {
+	for _, s := range QueueStrategies {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return FIFO
+}
+
+func queueStrategyNames() []string {
+	names := make([]string, len(QueueStrategies))
+	for idx, s := range QueueStrategies {
+		names[idx] = s.Name()
+	}
+	return names
+}
+
+type fifo struct{}
+
+func (fifo) Name() string { return "fifo" }
+
+func (fifo) Order(queue []*Song, _ Turns) []*Song { return slices.Clone(queue) }
+
+// roundRobin intercala por usuario: primero la primera canción pendiente de
+// cada uno, después la segunda, etc. (A1, B1, C1, A2, B2...). Dentro de cada
+// turno se respeta el orden de llegada. Las del autoplay (sin usuario)
+// cuentan como un usuario más. Cada ronda empieza por quien lleva más tiempo
+// sin sonar (según turns); quien no ha sonado aún va primero.
+type roundRobin struct{}
+
+func (roundRobin) Name() string { return "round_robin" }
+
+func (roundRobin) Order(queue []*Song, turns Turns) []*Song {
+	turn := make(map[*Song]int, len(queue))
+	count := make(map[string]int)
+	for _, song := range queue {
+		turn[song] = count[song.RequesterID]
+		count[song.RequesterID]++
+	}
+	ordered := slices.Clone(queue)
+	slices.SortStableFunc(ordered, func(a, b *Song) int {
+		if turn[a] != turn[b] {
+			return turn[a] - turn[b]
+		}
+		return turns.last[a.RequesterID] - turns.last[b.RequesterID]
+	})
+	return ordered
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// songs crea una cola a partir de títulos como "A1": la letra es quién la pide.
+func songs(titles string) []*Song {
+	var queue []*Song
+	for _, title := range strings.Fields(titles) {
+		queue = append(queue, &Song{Title: title, RequesterID: title[:1]})
+	}
+	return queue
+}
+
+func titles(queue []*Song) string {
+	var out []string
+	for _, s := range queue {
+		out = append(out, s.Title)
+	}
+	return strings.Join(out, " ")
+}
+
+// drain reproduce la cola como playNext: saca la primera según Order y anota
+// el turno. Comprueba además que en cada paso Order (lo que enseña /queue)
+// coincide con lo que queda por sonar.
+func drain(t *testing.T, strategy QueueStrategy, queue []*Song) string {
+	t.Helper()
+	var turns Turns
+	var played []*Song
+	var shown []string
+	for len(queue) > 0 {
+		ordered := strategy.Order(queue, turns)
+		shown = append(shown, titles(ordered))
+		next := ordered[0]
+		queue = slices.DeleteFunc(queue, func(s *Song) bool { return s == next })
+		turns.Served(next.RequesterID)
+		played = append(played, next)
+	}
+	for idx, want := range shown {
+		if got := titles(played[idx:]); got != want {
+			t.Errorf("tras %d canciones Order = %q, pero sonó %q", idx, want, got)
+		}
+	}
+	return titles(played)
+}
+
+func TestRoundRobinDrain(t *testing.T) {
+	tests := []struct {
+		queue string
+		want  string
+	}{
+		{"A1 A2 A3 B1", "A1 B1 A2 A3"},
+		{"A1 A2 A3 B1 C1", "A1 B1 C1 A2 A3"},
+		{"A1 A2 B1 B2 C1", "A1 B1 C1 A2 B2"},
+		{"A1 B1 A2 B2 A3", "A1 B1 A2 B2 A3"},
+		{"A1", "A1"},
+	}
+	for _, tt := range tests {
+		if got := drain(t, RoundRobin, songs(tt.queue)); got != tt.want {
+			t.Errorf("drain(%s) = %q, want %q", tt.queue, got, tt.want)
+		}
+	}
+}
+
+func TestRoundRobinNewcomerGoesFirst(t *testing.T) {
+	// A y B ya han sonado; C, que llega después, va antes en la ronda
+	var turns Turns
+	turns.Served("A")
+	turns.Served("B")
+	if got, want := titles(RoundRobin.Order(songs("A2 B2 C1"), turns)), "C1 A2 B2"; got != want {
+		t.Errorf("Order = %q, want %q", got, want)
+	}
+}
+
+func TestFIFODrain(t *testing.T) {
+	if got, want := drain(t, FIFO, songs("A1 A2 B1")), "A1 A2 B1"; got != want {
+		t.Errorf("drain = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"feints/internal/i18n"
+)
+
+// SettingKey identifica un ajuste configurable por guild.
+type SettingKey string
+
+const (
+	SettingLanguage        SettingKey = "language"
+	SettingMaxSongDuration SettingKey = "max_song_duration"
+	SettingMaxQueueLength  SettingKey = "max_queue_length"
+	SettingDJRole          SettingKey = "dj_role"
+	SettingDefaultVolume   SettingKey = "default_volume"
+	SettingAutoplay        SettingKey = "autoplay"
+	SettingAutoplaySource  SettingKey = "autoplay_source"
+	SettingAutoplayValue   SettingKey = "autoplay_value"
+	SettingCrossfade       SettingKey = "crossfade"
+	SettingSponsorBlock    SettingKey = "sponsorblock"
+	SettingSponsorBlockCat SettingKey = "sponsorblock_categories"
+	SettingVoteSkip        SettingKey = "vote_skip"
+	SettingVoteSkipPercent SettingKey = "vote_skip_percent"
+	SettingMusicChannel    SettingKey = "music_channel"
+	SettingMaxUserSongs    SettingKey = "max_user_songs"
+	SettingMaxUserDuration SettingKey = "max_user_duration"
+	SettingQueueStrategy   SettingKey = "queue_strategy"
+	SettingAloneTimeout    SettingKey = "alone_timeout"
+	SettingIdleTimeout     SettingKey = "idle_timeout"
+	SettingFilters         SettingKey = "filters"
+)
+
+// SettingType es el tipo de valor de un ajuste.
+type SettingType string
+
+const (
+	TypeString   SettingType = "string"
+	TypeInt      SettingType = "int"
+	TypeBool     SettingType = "bool"
+	TypeDuration SettingType = "duration"
+	TypeRole     SettingType = "role"
+	TypeChannel  SettingType = "channel"
+	TypeChoice   SettingType = "choice"
+	TypeList     SettingType = "list"
+	TypeFilters  SettingType = "filters" // FilterChain en JSON (lo cambia /filter)
+)
+
+// SettingDef describe un ajuste: su tipo y sus límites. La descripción
+// está en el catálogo de i18n como "setting.<clave>".
+type SettingDef struct {
+	Key        SettingKey
+	Type       SettingType
+	Choices    []string      // TypeChoice y TypeList: valores permitidos
+	Min, Max   int           // TypeInt
+	MinD, MaxD time.Duration // TypeDuration
+}
+
+// SettingDefs son todos los ajustes por guild, en el orden en que se muestran.
+var SettingDefs = []SettingDef{
+	{Key: SettingLanguage, Type: TypeChoice, Choices: i18n.Codes()},
+	{Key: SettingMaxSongDuration, Type: TypeDuration, MinD: time.Minute, MaxD: 3 * time.Hour},
+	{Key: SettingMaxQueueLength, Type: TypeInt, Min: 1, Max: MaxQueueCapacity},
+	{Key: SettingDJRole, Type: TypeRole},
+	{Key: SettingDefaultVolume, Type: TypeInt, Min: 0, Max: 200},
+	{Key: SettingAutoplay, Type: TypeBool},
+	{Key: SettingAutoplaySource, Type: TypeChoice, Choices: autoplaySourceNames()},
+	{Key: SettingAutoplayValue, Type: TypeString},
+	{Key: SettingCrossfade, Type: TypeDuration, MaxD: 12 * time.Second},
+	{Key: SettingSponsorBlock, Type: TypeBool},
+	{Key: SettingSponsorBlockCat, Type: TypeList, Choices: SegmentCategories},
+	{Key: SettingVoteSkip, Type: TypeBool},
+	{Key: SettingVoteSkipPercent, Type: TypeInt, Min: 1, Max: 100},
+	{Key: SettingMusicChannel, Type: TypeChannel},
+	{Key: SettingMaxUserSongs, Type: TypeInt, Min: 0, Max: MaxQueueCapacity},
+	{Key: SettingMaxUserDuration, Type: TypeDuration, MaxD: 24 * time.Hour},
+	{Key: SettingQueueStrategy, Type: TypeChoice, Choices: queueStrategyNames()},
+	{Key: SettingAloneTimeout, Type: TypeDuration, MaxD: 24 * time.Hour},
+	{Key: SettingIdleTimeout, Type: TypeDuration, MaxD: 24 * time.Hour},
+	{Key: SettingFilters, Type: TypeFilters},
+}
+
+// MaxQueueCapacity es el tope absoluto de canciones en cola por player.
+const MaxQueueCapacity = 500
+
+// LookupSetting devuelve la definición de un ajuste por su nombre.
+func LookupSetting(key string) (SettingDef, bool) {
+	for _, def := range SettingDefs {
+		if string(def.Key) == key {
+			return def, true
+		}
+	}
+	return SettingDef{}, false
+}
+
+// Normalize valida un valor introducido por el usuario y lo devuelve en su
+// forma canónica, que es como se guarda.
+func (d SettingDef) Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	switch d.Type {
+	case TypeInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return "", i18n.Errorf("settings.not_int", d.Key)
+		}
+		if n < d.Min || n > d.Max {
+			return "", i18n.Errorf("settings.int_range", d.Key, d.Min, d.Max)
+		}
+		return strconv.Itoa(n), nil
+
+	case TypeBool:
+		switch strings.ToLower(raw) {
+		case "true", "on", "yes", "si", "sí", "1":
+			return "true", nil
+		case "false", "off", "no", "0":
+			return "false", nil
+		}
+		return "", i18n.Errorf("settings.not_bool", d.Key)
+
+	case TypeDuration:
+		dur, err := parseDuration(raw)
+		if err != nil {
+			return "", i18n.Errorf("settings.not_duration", d.Key)
+		}
+		if dur < d.MinD || dur > d.MaxD {
+			return "", i18n.Errorf("settings.duration_range", d.Key, d.MinD, d.MaxD)
+		}
+		return dur.String(), nil
+
+	case TypeRole, TypeChannel:
+		// Acepta la mención (<@&id>, <#id>) o el id a secas
+		prefix, errKey := "<@&", "settings.not_role"
+		if d.Type == TypeChannel {
+			prefix, errKey = "<#", "settings.not_channel"
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(raw, prefix), ">")
+		if id == "" {
+			return "", nil
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return "", i18n.Errorf(errKey, d.Key)
+		}
+		return id, nil
+
+	case TypeChoice:
+		v := strings.ToLower(raw)
+		if !slices.Contains(d.Choices, v) {
+			return "", i18n.Errorf("settings.not_choice", d.Key, strings.Join(d.Choices, ", "))
+		}
+		return v, nil
+
+	case TypeList:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if item == "" {
+				continue
+			}
+			if !slices.Contains(d.Choices, item) {
+				return "", i18n.Errorf("settings.unknown_item", d.Key, item, strings.Join(d.Choices, ", "))
+			}
+			items = append(items, item)
+		}
+		return strings.Join(items, ","), nil
+
+	case TypeFilters:
+		f, err := ParseFilterChain(raw)
+		if err != nil {
+			return "", i18n.Errorf("settings.not_filters", d.Key)
+		}
+		if err := f.Validate(); err != nil {
+			return "", err
+		}
+		return f.Encode(), nil
+	}
+	return raw, nil
+}
+
+// parseDuration acepta duraciones de Go ("15m") o segundos a secas ("90").
+func parseDuration(raw string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(raw, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(raw)
+}
+
+func autoplaySourceNames() []string {
+	names := make([]string, len(AutoplaySources))
+	for idx, s := range AutoplaySources {
+		names[idx] = string(s)
+	}
+	return names
+}
+
+// --- Settings ---
+
+// Settings son los ajustes efectivos de un guild (valores propios sobre los
+// valores por defecto), en su forma canónica.
+type Settings map[SettingKey]string
+
+func (s Settings) String(key SettingKey) string { return s[key] }
+
+func (s Settings) Int(key SettingKey) int {
+	n, _ := strconv.Atoi(s[key])
+	return n
+}
+
+func (s Settings) Bool(key SettingKey) bool { return s[key] == "true" }
+
+func (s Settings) Duration(key SettingKey) time.Duration {
+	d, _ := parseDuration(s[key])
+	return d
+}
+
+func (s Settings) List(key SettingKey) []string {
+	if s[key] == "" {
+		return nil
+	}
+	return strings.Split(s[key], ",")
+}
+
+// Autoplay devuelve la configuración de autoplay del guild.
+func (s Settings) Autoplay() AutoplaySettings {
+	return AutoplaySettings{
+		Enabled: s.Bool(SettingAutoplay),
+		Source:  AutoplaySource(s.String(SettingAutoplaySource)),
+		Value:   s.String(SettingAutoplayValue),
+	}
+}
+
+// Filters devuelve los filtros de audio del guild.
+func (s Settings) Filters() FilterChain {
+	f, _ := ParseFilterChain(s[SettingFilters])
+	return f
+}
+
+// Segments devuelve qué segmentos de SponsorBlock salta el guild.
+func (s Settings) Segments() SegmentSettings {
+	return SegmentSettings{
+		Enabled:    s.Bool(SettingSponsorBlock),
+		Categories: s.List(SettingSponsorBlockCat),
+	}
+}
@@ -13,6 +13,63 @@ type Song struct {
 	Thumbnail string        `json:"thumbnail"`
 	URL       string        `json:"url"`
 	Path      string        `json:"path"`
+	Genre     string        `json:"genre"`
+	Chapters  []Chapter     `json:"chapters"`
+	// Start y End acotan una pista virtual (un capítulo) dentro del fichero.
+	// End == 0 significa hasta el final.
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	// RequesterID es el usuario de Discord que pidió la canción ("" = autoplay),
+	// RequesterName su nombre visible y RequestedAt cuándo la pidió.
+	RequesterID   string    `json:"requester_id"`
+	RequesterName string    `json:"requester_name"`
+	RequestedAt   time.Time `json:"requested_at"`
+}
+
+// Chapter es un capítulo de un vídeo largo (álbum completo, mix...).
+type Chapter struct {
+	Title string        `json:"title"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// IsClip indica si la canción es una pista virtual dentro de un fichero mayor.
+func (s Song) IsClip() bool { return s.Start > 0 || s.End > 0 }
+
+// Length devuelve lo que dura la reproducción de la canción (o del clip).
+func (s Song) Length() time.Duration {
+	end := s.End
+	if end == 0 {
+		end = s.Duration
+	}
+	return max(end-s.Start, 0)
+}
+
+// Clip devuelve una pista virtual que reproduce sólo el capítulo c.
+func (s Song) Clip(c Chapter) Song {
+	clip := s
+	clip.Title = fmt.Sprintf("%s — %s", s.Title, c.Title)
+	clip.Start, clip.End = c.Start, c.End
+	clip.Chapters = nil
+	return clip
+}
+
+// ChapterAt devuelve el índice del capítulo que contiene pos, o -1.
+func (s Song) ChapterAt(pos time.Duration) int {
+	for idx, c := range s.Chapters {
+		if pos >= c.Start && (c.End == 0 || pos < c.End) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Key identifica la canción: su URL si la tiene o, si no, su ruta local.
+func (s Song) Key() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.Path
 }
 
 // String devuelve una representación legible de la canción.
@@ -21,4 +78,40 @@ func (s Song) String() string {
 		"Song{Title=%q, Uploader=%q, Duration=%s, URL=%s, Path=%s}",
 		s.Title, s.Uploader, s.Duration.String(), s.URL, s.Path,
 	)
-}
\ No newline at end of file
+}
+
+// Segment es un tramo de una canción marcado por SponsorBlock o por capítulos
+// (intro, outro, parte no musical...).
+type Segment struct {
+	Start    time.Duration `json:"start"`
+	End      time.Duration `json:"end"`
+	Category string        `json:"category"`
+}
+
+// SegmentSettings indica qué segmentos se saltan en un guild.
+type SegmentSettings struct {
+	Enabled    bool     `json:"enabled"`
+	Categories []string `json:"categories"`
+}
+
+// SegmentCategories son las categorías de SponsorBlock conocidas.
+var SegmentCategories = []string{
+	"sponsor", "intro", "outro", "selfpromo", "preview", "filler", "interaction", "music_offtopic",
+}
+
+// DefaultSegmentCategories son las categorías que se saltan si no se indica otra cosa.
+var DefaultSegmentCategories = []string{"music_offtopic", "sponsor", "selfpromo"}
+
+// Skips indica si un segmento de la categoría dada debe saltarse.
+func (s SegmentSettings) Skips(category string) bool {
+	categories := s.Categories
+	if len(categories) == 0 {
+		categories = DefaultSegmentCategories
+	}
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package i18n
+
+// en es el catálogo en inglés.
+var en = map[string]string{
+	// Generales
+	"error":                  "❌ %s",
+	"error.internal":         "❌ Something went wrong running the command. If it keeps happening, share this code with an admin: `%s`.",
+	"error.guild_only":       "❌ The bot's commands only work in a server.",
+	"rate.limited":           "⏳ Slow down; wait %ds.",
+	"not_in_voice":           "❌ You're not in a voice channel.",
+	"nothing_playing":        "📭 Nothing is playing.",
+	"on":                     "on",
+	"off":                    "off",
+	"default_suffix":         " _(default)_",
+	"state.idle":             "idle",
+	"state.playing":          "playing",
+	"state.paused":           "paused",
+	"perm.admin_only":        "⛔ Only administrators can use /%s.",
+	"perm.requester_or_dj":   "⛔ Only whoever requested the song or a <@&%s> can use /%s.",
+	"perm.dj":                "⛔ You need the <@&%s> role to use /%s.",
+	"play.no_song":           "❌ No song was given.",
+	"play.empty_query":       "❌ The search can't be empty.",
+	"play.queue_full":        "❌ The queue is full (%d songs max).",
+	"play.added":             "🎶 Added to the queue: **%s**",
+	"play.no_chapters":       "❌ **%s** has no chapters.",
+	"play.chapters_too_many": "❌ The %d chapters don't fit in the queue (%d songs max).",
+	"play.chapters_added":    "🎶 Added %d chapters from **%s**",
+	"play.user_songs":        "❌ You already have %d songs queued (%d per person max).",
+	"play.user_duration":     "❌ That would exceed the %s of queued time allowed per person.",
+	"queue.empty":            "📭 The queue is empty.",
+	"queue.title":            "🎶 Queue",
+	"queue.now_playing":      "**Now playing:** %s `%s / %s`",
+	"queue.footer":           "Page %d/%d · %d songs queued · %s remaining",
+	"skip.done":              "⏭ Skipping to the next song.",
+	"vote.passed":            "⏭ Vote passed (%d/%d): skipping.",
+	"vote.registered":        "🗳 Vote counted: %d/%d votes to skip.",
+	"stop.done":              "⏹ Playback stopped and queue cleared.",
+	"clear.done":             "⏹ Queue cleared.",
+	"status":                 "Status: %s",
+	"pause.paused":           "⏸ Playback paused.",
+	"pause.resumed":          "▶️ Playback resumed.",
+	"shuffle.done":           "🔀 Queue shuffled.",
+	"join.moved":             "🔊 Moving to <#%s>, queue intact.",
+	"join.already":           "🔊 I'm already in <#%s>.",
+	"join.failed":            "❌ I couldn't move to your voice channel.",
+	"shutdown.notice":        "🔌 The bot is restarting. %d songs were saved and will be back in the queue with the next /play or /join.",
+	"shutdown.rejected":      "⏳ The bot is restarting; try again in a few seconds.",
+	"voice.other_channel":    "❌ You need to be in <#%s>, where the bot is. Use /join to bring it over.",
+	"voice.failed":           "❌ The voice connection was lost and couldn't be recovered; playback stopped.",
+	"song.not_found":         "The video doesn't exist or isn't available.",
+	"song.too_long":          "The song is too long (max %s).",
+	"song.age_restricted":    "The video is age-restricted and can't be played.",
+	"song.geo_blocked":       "The video isn't available in the bot's region.",
+	"song.rate_limited":      "YouTube is rate-limiting requests; try again in a few minutes.",
+	"song.failed":            "Couldn't fetch the video information.",
+	"song.skipped":           "⏭ Skipping **%s**. %s",
+	"np.uploader":            "Artist",
+	"np.duration":            "Duration",
+	"np.requester":           "Requested by",
+	"np.autoplay":            "Autoplay",
+
+	"autoplay.unknown_source": "❌ Unknown autoplay source: %s",
+	"autoplay.needs_value":    "❌ The %s source needs a value.",
+	"autoplay.no_playlist":    "❌ There's no playlist called **%s**.",
+	"autoplay.save_failed":    "⚠️ Autoplay updated, but the settings couldn't be saved.",
+	"autoplay.status":         "🔁 Autoplay %s · source: %s",
+	"playlist.saved":          "💾 Saved playlist **%s** with %d songs. Use it with /autoplay source:playlist.",
+	"playlist.empty":          "📭 Nothing playing or queued to save.",
+	"playlist.no_title":       "❌ The playlist needs a name.",
+	"playlist.save_failed":    "❌ Couldn't save the playlist.",
+	"playlist.list":           "📂 Saved playlists: %s",
+	"playlist.none":           "📂 No saved playlists. Create one with /playlist save.",
+	"playlist.list_failed":    "❌ Couldn't read the playlists.",
+
+	"filter.status":        "🎛 Active filters: %s",
+	"filter.none":          "none",
+	"filter.save_failed":   "⚠️ Filters applied, but they couldn't be saved.",
+	"filter.bass_nan":      "❌ Bass boost must be a number of dB.",
+	"filter.value_nan":     "❌ Give a numeric value for %s (e.g. 1.25).",
+	"filter.unknown":       "❌ Unknown effect: %s",
+	"filter.unknown_eq":    "unknown equalizer preset: %s",
+	"filter.bass_range":    "bass boost out of range (0-%d dB)",
+	"filter.speed_range":   "speed/pitch out of range (%.1f-%.1f)",
+	"sponsorblock.status":  "⏩ Segment skipping %s · categories: %s",
+	"chapters.none":        "📖 **%s** has no chapters.",
+	"chapters.header":      "📖 Chapters of **%s**:\n",
+	"chapter.none":         "❌ The current song has no chapters.",
+	"chapter.usage":        "❌ Use next, prev or the chapter number.",
+	"chapter.out_of_range": "❌ There's no chapter at that position.",
+	"chapter.jump":         "⏩ Chapter %d: **%s**",
+
+	// /settings
+	"settings.header":         "⚙️ Server settings:\n",
+	"settings.updated":        "✅ %s = %s",
+	"settings.reset_failed":   "❌ Couldn't reset the settings.",
+	"settings.reset_all":      "♻️ All settings are back to their defaults.",
+	"settings.reset_one":      "♻️ %s is back to its default.",
+	"settings.unknown":        "unknown setting: %s",
+	"settings.not_int":        "%s must be a whole number",
+	"settings.int_range":      "%s must be between %d and %d",
+	"settings.not_bool":       "%s must be on or off",
+	"settings.not_duration":   "%s must be a duration (e.g. 90s, 15m)",
+	"settings.duration_range": "%s must be between %s and %s",
+	"settings.not_role":       "%s must be a role",
+	"settings.not_channel":    "%s must be a channel",
+	"settings.not_choice":     "%s must be one of: %s",
+	"settings.unknown_item":   "%s: unknown value %q (valid: %s)",
+	"settings.not_filters":    "%s is changed with /filter",
+
+	"setting.language":                "Reply language; when unset, each user's own",
+	"setting.max_song_duration":       "Maximum song length",
+	"setting.max_queue_length":        "Maximum songs in the queue",
+	"setting.dj_role":                 "DJ role (empty = none)",
+	"setting.default_volume":          "Starting volume in %",
+	"setting.autoplay":                "Autoplay enabled",
+	"setting.autoplay_source":         "Autoplay source",
+	"setting.autoplay_value":          "Autoplay playlist or genre",
+	"setting.crossfade":               "Crossfade between songs",
+	"setting.sponsorblock":            "Skip SponsorBlock segments",
+	"setting.sponsorblock_categories": "SponsorBlock categories to skip",
+	"setting.vote_skip":               "Non-DJs vote to skip",
+	"setting.vote_skip_percent":       "% of listeners needed to skip",
+	"setting.music_channel":           "Channel for the now-playing message (empty = where playback started)",
+	"setting.max_user_songs":          "Queued songs per person (0 = unlimited)",
+	"setting.max_user_duration":       "Queued time per person (0 = unlimited)",
+	"setting.queue_strategy":          "Queue order: fifo or round_robin (turns between requesters)",
+	"setting.alone_timeout":           "Time alone in the channel before disconnecting (0 = never)",
+	"setting.idle_timeout":            "Time with nothing playing before disconnecting (0 = never)",
+	"setting.filters":                 "Audio filters (changed with /filter)",
+
+	// Definición de los comandos (ver es.go)
+	"cmd.play":                     "Play a song",
+	"cmd.play.search":              "Song name or URL",
+	"cmd.play.split_chapters":      "Queue each chapter of an album or mix as its own track",
+	"cmd.stop":                     "Stop playback and disconnect",
+	"cmd.queue":                    "Show the song queue",
+	"cmd.skip":                     "Skip to the next song",
+	"cmd.clear":                    "Clear the queue",
+	"cmd.status":                   "Show the current status",
+	"cmd.pause":                    "Pause or resume playback",
+	"cmd.shuffle":                  "Shuffle the queue",
+	"cmd.nowplaying":               "Show the current song with its controls",
+	"cmd.join":                     "Bring the bot to your voice channel, keeping the queue",
+	"cmd.autoplay":                 "Turn autoplay on or off, or configure it",
+	"cmd.autoplay.mode":            "on, off or status",
+	"cmd.autoplay.source":          "Where autoplay picks songs from",
+	"cmd.autoplay.source.smart":    "smart (related + library)",
+	"cmd.autoplay.source.local":    "local library",
+	"cmd.autoplay.source.playlist": "saved playlist",
+	"cmd.autoplay.source.related":  "related",
+	"cmd.autoplay.source.genre":    "genre",
+	"cmd.autoplay.value":           "Playlist or genre name",
+	"cmd.filter":                   "Apply audio effects to playback",
+	"cmd.filter.effect":            "Effect to apply or remove",
+	"cmd.filter.effect.eq":         "equalizer",
+	"cmd.filter.effect.speed":      "speed",
+	"cmd.filter.effect.pitch":      "pitch",
+	"cmd.filter.effect.reset":      "remove all",
+	"cmd.filter.effect.status":     "show active",
+	"cmd.filter.value":             "dB, equalizer preset (%s) or multiplier",
+	"cmd.sponsorblock":             "Skip intros, outros and non-music parts of videos",
+	"cmd.sponsorblock.mode":        "on, off or status",
+	"cmd.sponsorblock.categories":  "Comma-separated categories (e.g. music_offtopic,intro,outro)",
+	"cmd.chapters":                 "List the chapters of the current song",
+	"cmd.chapter":                  "Jump to another chapter of the current song",
+	"cmd.chapter.target":           "next, prev or chapter number",
+	"cmd.playlist":                 "Save the queue as a playlist for autoplay",
+	"cmd.playlist.save":            "Save the current song and the queue as a playlist",
+	"cmd.playlist.save.title":      "Playlist name (replaces one with the same name)",
+	"cmd.playlist.list":            "Show the saved playlists",
+	"cmd.settings":                 "Bot settings for this server (admins only)",
+	"cmd.settings.get":             "Show the settings",
+	"cmd.settings.get.key":         "Setting",
+	"cmd.settings.set":             "Change a setting",
+	"cmd.settings.set.key":         "Setting",
+	"cmd.settings.set.value":       "New value",
+	"cmd.settings.reset":           "Reset a setting (or all) to its default",
+	"cmd.settings.reset.key":       "Setting",
+}
@@ -0,0 +1,202 @@
+package i18n
+
+// es es el catálogo en español, el completo (Fallback).
+var es = map[string]string{
+	// Generales
+	"error":                  "❌ %s",
+	"error.internal":         "❌ Algo salió mal al ejecutar el comando. Si se repite, comparte este código con un administrador: `%s`.",
+	"error.guild_only":       "❌ Los comandos del bot sólo funcionan en un servidor.",
+	"rate.limited":           "⏳ Vas demasiado rápido; espera %ds.",
+	"not_in_voice":           "❌ No estás en un canal de voz.",
+	"nothing_playing":        "📭 No está sonando nada.",
+	"on":                     "activado",
+	"off":                    "desactivado",
+	"default_suffix":         " _(por defecto)_",
+	"state.idle":             "en espera",
+	"state.playing":          "reproduciendo",
+	"state.paused":           "en pausa",
+	"perm.admin_only":        "⛔ Sólo los administradores pueden usar /%s.",
+	"perm.requester_or_dj":   "⛔ Sólo quien pidió la canción o un <@&%s> puede usar /%s.",
+	"perm.dj":                "⛔ Necesitas el rol <@&%s> para usar /%s.",
+	"play.no_song":           "❌ No se proporcionó ninguna canción.",
+	"play.empty_query":       "❌ La búsqueda no puede estar vacía.",
+	"play.queue_full":        "❌ La cola está llena (máximo %d canciones).",
+	"play.added":             "🎶 Añadido a la cola: **%s**",
+	"play.no_chapters":       "❌ **%s** no tiene capítulos.",
+	"play.chapters_too_many": "❌ Los %d capítulos no caben en la cola (máximo %d canciones).",
+	"play.chapters_added":    "🎶 Añadidos %d capítulos de **%s**",
+	"play.user_songs":        "❌ Ya tienes %d canciones en cola (máximo %d por persona).",
+	"play.user_duration":     "❌ Superarías el máximo de %s en cola por persona.",
+	"queue.empty":            "📭 La cola está vacía.",
+	"queue.title":            "🎶 Cola",
+	"queue.now_playing":      "**Sonando:** %s `%s / %s`",
+	"queue.footer":           "Página %d/%d · %d canciones en cola · %s restantes",
+	"skip.done":              "⏭ Saltando a la siguiente canción.",
+	"vote.passed":            "⏭ Votación superada (%d/%d): saltando.",
+	"vote.registered":        "🗳 Voto registrado: %d/%d votos para saltar.",
+	"stop.done":              "⏹ Reproducción detenida y cola limpiada.",
+	"clear.done":             "⏹ Cola limpiada.",
+	"status":                 "Estado: %s",
+	"pause.paused":           "⏸ Reproducción en pausa.",
+	"pause.resumed":          "▶️ Reproducción reanudada.",
+	"shuffle.done":           "🔀 Cola mezclada.",
+	"join.moved":             "🔊 Me muevo a <#%s> con la cola intacta.",
+	"join.already":           "🔊 Ya estoy en <#%s>.",
+	"join.failed":            "❌ No pude moverme a tu canal de voz.",
+	"shutdown.notice":        "🔌 El bot se está reiniciando. Se han guardado %d canciones y volverán a la cola con el próximo /play o /join.",
+	"shutdown.rejected":      "⏳ El bot se está reiniciando; vuelve a intentarlo en unos segundos.",
+	"voice.other_channel":    "❌ Tienes que estar en <#%s>, donde está el bot. Usa /unirse para traerlo.",
+	"voice.failed":           "❌ Se perdió la conexión de voz y no se pudo recuperar; reproducción detenida.",
+	"song.not_found":         "El vídeo no existe o no está disponible.",
+	"song.too_long":          "La canción dura demasiado (máximo %s).",
+	"song.age_restricted":    "El vídeo tiene restricción de edad y no se puede reproducir.",
+	"song.geo_blocked":       "El vídeo no está disponible en la región del bot.",
+	"song.rate_limited":      "YouTube está limitando las peticiones; prueba de nuevo en unos minutos.",
+	"song.failed":            "No se pudo obtener la información del vídeo.",
+	"song.skipped":           "⏭ Se salta **%s**. %s",
+	"np.uploader":            "Artista",
+	"np.duration":            "Duración",
+	"np.requester":           "Pedida por",
+	"np.autoplay":            "Autoplay",
+
+	"autoplay.unknown_source": "❌ Fuente de autoplay desconocida: %s",
+	"autoplay.needs_value":    "❌ La fuente %s necesita un valor.",
+	"autoplay.no_playlist":    "❌ No existe la playlist **%s**.",
+	"autoplay.save_failed":    "⚠️ Autoplay actualizado, pero no se pudo guardar la configuración.",
+	"autoplay.status":         "🔁 Autoplay %s · fuente: %s",
+	"playlist.saved":          "💾 Playlist **%s** guardada con %d canciones. Úsala con /autoplay source:playlist.",
+	"playlist.empty":          "📭 No hay nada sonando ni en cola que guardar.",
+	"playlist.no_title":       "❌ La playlist necesita un nombre.",
+	"playlist.save_failed":    "❌ No se pudo guardar la playlist.",
+	"playlist.list":           "📂 Playlists guardadas: %s",
+	"playlist.none":           "📂 No hay playlists guardadas. Crea una con /playlist save.",
+	"playlist.list_failed":    "❌ No se pudieron leer las playlists.",
+
+	"filter.status":        "🎛 Filtros activos: %s",
+	"filter.none":          "ninguno",
+	"filter.save_failed":   "⚠️ Filtros aplicados, pero no se pudieron guardar.",
+	"filter.bass_nan":      "❌ El bass boost debe ser un número de dB.",
+	"filter.value_nan":     "❌ Indica un valor numérico para %s (p. ej. 1.25).",
+	"filter.unknown":       "❌ Efecto desconocido: %s",
+	"filter.unknown_eq":    "preset de ecualizador desconocido: %s",
+	"filter.bass_range":    "bass boost fuera de rango (0-%d dB)",
+	"filter.speed_range":   "velocidad/tono fuera de rango (%.1f-%.1f)",
+	"sponsorblock.status":  "⏩ Salto de segmentos %s · categorías: %s",
+	"chapters.none":        "📖 **%s** no tiene capítulos.",
+	"chapters.header":      "📖 Capítulos de **%s**:\n",
+	"chapter.none":         "❌ La canción actual no tiene capítulos.",
+	"chapter.usage":        "❌ Usa next, prev o el número del capítulo.",
+	"chapter.out_of_range": "❌ No hay capítulo en esa posición.",
+	"chapter.jump":         "⏩ Capítulo %d: **%s**",
+
+	// /settings
+	"settings.header":         "⚙️ Ajustes del servidor:\n",
+	"settings.updated":        "✅ %s = %s",
+	"settings.reset_failed":   "❌ No se pudieron restablecer los ajustes.",
+	"settings.reset_all":      "♻️ Todos los ajustes vuelven a sus valores por defecto.",
+	"settings.reset_one":      "♻️ %s vuelve a su valor por defecto.",
+	"settings.unknown":        "ajuste desconocido: %s",
+	"settings.not_int":        "%s debe ser un número entero",
+	"settings.int_range":      "%s debe estar entre %d y %d",
+	"settings.not_bool":       "%s debe ser on u off",
+	"settings.not_duration":   "%s debe ser una duración (p. ej. 90s, 15m)",
+	"settings.duration_range": "%s debe estar entre %s y %s",
+	"settings.not_role":       "%s debe ser un rol",
+	"settings.not_channel":    "%s debe ser un canal",
+	"settings.not_choice":     "%s debe ser uno de: %s",
+	"settings.unknown_item":   "%s: valor desconocido %q (válidos: %s)",
+	"settings.not_filters":    "%s se cambia con /filter",
+
+	"setting.language":                "Idioma de las respuestas; sin fijar, el de cada usuario",
+	"setting.max_song_duration":       "Duración máxima de una canción",
+	"setting.max_queue_length":        "Canciones máximas en la cola",
+	"setting.dj_role":                 "Rol DJ (vacío = ninguno)",
+	"setting.default_volume":          "Volumen inicial en %",
+	"setting.autoplay":                "Autoplay activado",
+	"setting.autoplay_source":         "Fuente del autoplay",
+	"setting.autoplay_value":          "Playlist o género del autoplay",
+	"setting.crossfade":               "Crossfade entre canciones",
+	"setting.sponsorblock":            "Saltar segmentos de SponsorBlock",
+	"setting.sponsorblock_categories": "Categorías de SponsorBlock a saltar",
+	"setting.vote_skip":               "Los no DJ votan para saltar",
+	"setting.vote_skip_percent":       "% de oyentes necesario para saltar",
+	"setting.music_channel":           "Canal del mensaje de reproducción (vacío = donde se pidió)",
+	"setting.max_user_songs":          "Canciones en cola por persona (0 = sin límite)",
+	"setting.max_user_duration":       "Tiempo en cola por persona (0 = sin límite)",
+	"setting.queue_strategy":          "Orden de la cola: fifo o round_robin (por turnos entre quienes piden)",
+	"setting.alone_timeout":           "Tiempo a solas en el canal antes de desconectarse (0 = nunca)",
+	"setting.idle_timeout":            "Tiempo sin reproducir nada antes de desconectarse (0 = nunca)",
+	"setting.filters":                 "Filtros de audio (se cambian con /filter)",
+
+	// Definición de los comandos: cmd.<comando>[.<opción>[.<valor>]] es la
+	// descripción (o el nombre de la opción elegible) y el sufijo .name, el
+	// nombre localizado.
+	"cmd.play.name":                "reproducir",
+	"cmd.play":                     "Reproduce una canción",
+	"cmd.play.search":              "Nombre o URL de la canción",
+	"cmd.play.split_chapters":      "Encola cada capítulo de un álbum o mix como una pista",
+	"cmd.stop.name":                "detener",
+	"cmd.stop":                     "Detiene la reproducción y se desconecta",
+	"cmd.queue.name":               "cola",
+	"cmd.queue":                    "Muestra la cola de canciones",
+	"cmd.skip.name":                "saltar",
+	"cmd.skip":                     "Salta a la siguiente canción",
+	"cmd.clear.name":               "limpiar",
+	"cmd.clear":                    "Limpia la cola",
+	"cmd.status.name":              "estado",
+	"cmd.status":                   "Muestra el estado actual",
+	"cmd.pause.name":               "pausa",
+	"cmd.pause":                    "Pausa o reanuda la reproducción",
+	"cmd.shuffle.name":             "mezclar",
+	"cmd.shuffle":                  "Mezcla la cola",
+	"cmd.nowplaying.name":          "sonando",
+	"cmd.nowplaying":               "Muestra la canción actual con sus controles",
+	"cmd.join.name":                "unirse",
+	"cmd.join":                     "Trae el bot a tu canal de voz sin perder la cola",
+	"cmd.autoplay":                 "Activa, desactiva o configura el autoplay",
+	"cmd.autoplay.mode":            "on, off o status",
+	"cmd.autoplay.source":          "De dónde saca canciones el autoplay",
+	"cmd.autoplay.source.smart":    "smart (relacionadas + biblioteca)",
+	"cmd.autoplay.source.local":    "biblioteca local",
+	"cmd.autoplay.source.playlist": "playlist guardada",
+	"cmd.autoplay.source.related":  "relacionadas",
+	"cmd.autoplay.source.genre":    "género",
+	"cmd.autoplay.value":           "Nombre de la playlist o género",
+	"cmd.filter.name":              "filtro",
+	"cmd.filter":                   "Aplica efectos de audio a la reproducción",
+	"cmd.filter.effect":            "Efecto a aplicar o quitar",
+	"cmd.filter.effect.eq":         "ecualizador",
+	"cmd.filter.effect.speed":      "velocidad",
+	"cmd.filter.effect.pitch":      "tono",
+	"cmd.filter.effect.reset":      "quitar todos",
+	"cmd.filter.effect.status":     "ver activos",
+	"cmd.filter.value":             "dB, preset de ecualizador (%s) o multiplicador",
+	"cmd.sponsorblock":             "Salta intros, outros y partes no musicales de los vídeos",
+	"cmd.sponsorblock.mode":        "on, off o status",
+	"cmd.sponsorblock.categories":  "Categorías separadas por comas (p. ej. music_offtopic,intro,outro)",
+	"cmd.chapters.name":            "capitulos",
+	"cmd.chapters":                 "Muestra los capítulos de la canción actual",
+	"cmd.chapter.name":             "capitulo",
+	"cmd.chapter":                  "Salta a otro capítulo de la canción actual",
+	"cmd.chapter.target":           "next, prev o número de capítulo",
+	"cmd.playlist.name":            "lista",
+	"cmd.playlist":                 "Guarda la cola como playlist para el autoplay",
+	"cmd.playlist.save.name":       "guardar",
+	"cmd.playlist.save":            "Guarda la canción actual y la cola como playlist",
+	"cmd.playlist.save.title.name": "nombre",
+	"cmd.playlist.save.title":      "Nombre de la playlist (reemplaza a una con el mismo)",
+	"cmd.playlist.list.name":       "ver",
+	"cmd.playlist.list":            "Muestra las playlists guardadas",
+	"cmd.settings.name":            "ajustes",
+	"cmd.settings":                 "Ajustes del bot en este servidor (sólo administradores)",
+	"cmd.settings.get.name":        "ver",
+	"cmd.settings.get":             "Muestra los ajustes",
+	"cmd.settings.get.key":         "Ajuste",
+	"cmd.settings.set.name":        "cambiar",
+	"cmd.settings.set":             "Cambia un ajuste",
+	"cmd.settings.set.key":         "Ajuste",
+	"cmd.settings.set.value":       "Nuevo valor",
+	"cmd.settings.reset.name":      "restablecer",
+	"cmd.settings.reset":           "Vuelve un ajuste (o todos) a su valor por defecto",
+	"cmd.settings.reset.key":       "Ajuste",
+}
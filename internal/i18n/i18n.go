@@ -0,0 +1,150 @@
+// Package i18n traduce los mensajes que el bot muestra a los usuarios.
+//
+// Cada idioma es un catálogo clave → texto (ver es.go y en.go). Los textos
+// pueden llevar verbos de fmt; los argumentos se pasan a T. Para añadir un
+// idioma basta con un catálogo nuevo registrado en catalogs y sus locales
+// de Discord en discordLocales.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Lang es un idioma soportado, identificado por su código ISO 639-1.
+type Lang string
+
+const (
+	Spanish Lang = "es"
+	English Lang = "en"
+)
+
+// Fallback es el idioma con el catálogo completo; se usa cuando a otro
+// le falta una clave.
+const Fallback = Spanish
+
+var catalogs = map[Lang]map[string]string{
+	Spanish: es,
+	English: en,
+}
+
+// discordLocales son los locales de Discord que corresponden a cada idioma.
+var discordLocales = map[Lang][]discordgo.Locale{
+	Spanish: {discordgo.SpanishES, discordgo.SpanishLATAM},
+	English: {discordgo.EnglishUS, discordgo.EnglishGB},
+}
+
+// Codes devuelve los códigos de los idiomas soportados, con Fallback primero.
+func Codes() []string {
+	codes := []string{string(Fallback)}
+	for _, lang := range slices.Sorted(maps.Keys(catalogs)) {
+		if lang != Fallback {
+			codes = append(codes, string(lang))
+		}
+	}
+	return codes
+}
+
+// Parse devuelve el idioma de un código ("es", "en"...), si está soportado.
+func Parse(code string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(code)))
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// FromLocale devuelve el idioma de un locale de Discord ("es-ES", "en-US"...).
+func FromLocale(locale discordgo.Locale) (Lang, bool) {
+	code, _, _ := strings.Cut(string(locale), "-")
+	return Parse(code)
+}
+
+// Resolve elige el idioma de una respuesta. Manda el idioma fijado por el
+// guild; si no lo ha fijado, el del usuario, después el del servidor y por
+// último def.
+func Resolve(guildLang string, locale discordgo.Locale, guildLocale *discordgo.Locale, def string) Lang {
+	if lang, ok := Parse(guildLang); ok {
+		return lang
+	}
+	if lang, ok := FromLocale(locale); ok {
+		return lang
+	}
+	if guildLocale != nil {
+		if lang, ok := FromLocale(*guildLocale); ok {
+			return lang
+		}
+	}
+	if lang, ok := Parse(def); ok {
+		return lang
+	}
+	return Fallback
+}
+
+// Has indica si el idioma tiene la clave (sin recurrir a Fallback).
+func Has(lang Lang, key string) bool {
+	_, ok := catalogs[lang][key]
+	return ok
+}
+
+// T devuelve el texto de key en lang con args aplicados. Si falta en lang
+// se usa Fallback, y si falta también ahí, la propia clave.
+func T(lang Lang, key string, args ...any) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		if text, ok = catalogs[Fallback][key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Localizations devuelve key traducida a todos los locales de Discord
+// soportados, para NameLocalizations y DescriptionLocalizations. Sólo
+// incluye los idiomas que tienen la clave.
+func Localizations(key string, args ...any) map[discordgo.Locale]string {
+	out := make(map[discordgo.Locale]string)
+	for lang, locales := range discordLocales {
+		if !Has(lang, key) {
+			continue
+		}
+		for _, locale := range locales {
+			out[locale] = T(lang, key, args...)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// --- Errores traducibles ---
+
+// Error es un error cuyo mensaje sale del catálogo, de modo que puede
+// mostrarse al usuario en su idioma.
+type Error struct {
+	Key  string
+	Args []any
+}
+
+// Errorf crea un error traducible.
+func Errorf(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+func (e *Error) Error() string { return T(Fallback, e.Key, e.Args...) }
+
+// Message traduce err si es (o envuelve) un Error; si no, devuelve su texto.
+func Message(lang Lang, err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return T(lang, e.Key, e.Args...)
+	}
+	return err.Error()
+}
@@ -0,0 +1,438 @@
+package infra
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"layeh.com/gopus"
+)
+
+// Parámetros de audio que espera Discord: 48kHz estéreo en frames de 20ms.
+const (
+	frameRate     = 48000
+	channels      = 2
+	frameSize     = 960 // muestras por canal en cada frame
+	frameDuration = 20 * time.Millisecond
+	maxOpusBytes  = frameSize * channels * 2
+)
+
+// --- pcmSource ---
+
+// sourceOptions ajusta cómo ffmpeg decodifica un fichero.
+type sourceOptions struct {
+	// Start es la posición inicial dentro del fichero.
+	Start time.Duration
+	// End, si no es 0, es la posición del fichero donde se deja de leer
+	// (pistas virtuales de un capítulo).
+	End time.Duration
+	// Length es la duración total del fichero, si se conoce. El mixer la usa
+	// para empezar un crossfade antes del final.
+	Length time.Duration
+	// Filters es la cadena de filtros de ffmpeg (-af), "" para ninguno.
+	Filters string
+	// Tempo es cuánto avanza el fichero por segundo reproducido (0 = 1).
+	Tempo float64
+}
+
+// pcmSource decodifica un fichero de audio con ffmpeg y lo expone como
+// frames PCM de 20ms listos para mezclar.
+type pcmSource struct {
+	cmd    *exec.Cmd
+	frames chan []int16
+	opts   sourceOptions
+	played atomic.Int64 // frames consumidos por el mixer
+
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	// Fundido; sólo lo toca el mixer con su mutex tomado
+	gain       float64
+	gainStep   float64
+	gainTarget float64
+	fadeLeft   int
+}
+
+func newPCMSource(path string, opts sourceOptions) (*pcmSource, error) {
+	cmd := exec.Command("ffmpeg", ffmpegArgs(path, opts)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg stdout error: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("ffmpeg start error: %w", err)
+	}
+
+	if opts.Tempo <= 0 {
+		opts.Tempo = 1
+	}
+	src := &pcmSource{
+		cmd:    cmd,
+		frames: make(chan []int16, 50), // ~1s de buffer
+		opts:   opts,
+		closed: make(chan struct{}),
+		gain:   1,
+	}
+	go src.read(bufio.NewReaderSize(stdout, 16384))
+	return src, nil
+}
+
+// ffmpegArgs son los argumentos de ffmpeg para decodificar path a PCM desde
+// opts.Start y, en un clip, sólo hasta opts.End.
+func ffmpegArgs(path string, opts sourceOptions) []string {
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if opts.Start > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.3f", opts.Start.Seconds()))
+	}
+	if opts.End > opts.Start {
+		args = append(args, "-t", fmt.Sprintf("%.3f", (opts.End-opts.Start).Seconds()))
+	}
+	args = append(args, "-i", path)
+	if opts.Filters != "" {
+		args = append(args, "-af", opts.Filters)
+	}
+	args = append(args,
+		"-f", "s16le",
+		"-ar", fmt.Sprint(frameRate),
+		"-ac", fmt.Sprint(channels),
+		"pipe:1",
+	)
+	return args
+}
+
+// read alimenta frames hasta el EOF de ffmpeg o hasta que se cierre la fuente.
+func (s *pcmSource) read(r io.Reader) {
+	defer close(s.frames)
+	defer s.cmd.Wait()
+	for {
+		buf := make([]int16, frameSize*channels)
+		if err := binary.Read(r, binary.LittleEndian, buf); err != nil {
+			return
+		}
+		select {
+		case s.frames <- buf:
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// Position devuelve la posición de reproducción dentro del fichero.
+func (s *pcmSource) Position() time.Duration {
+	played := time.Duration(s.played.Load()) * frameDuration
+	return s.opts.Start + time.Duration(float64(played)*s.opts.Tempo)
+}
+
+// Remaining devuelve el tiempo real de reproducción que falta para el
+// final (teniendo en cuenta el tempo), o -1 si no se conoce la duración.
+func (s *pcmSource) Remaining() time.Duration {
+	if s.opts.Length <= 0 {
+		return -1
+	}
+	left := max(s.opts.Length-s.Position(), 0)
+	return time.Duration(float64(left) / s.opts.Tempo)
+}
+
+// Done se cierra cuando la fuente ha terminado o se ha descartado.
+func (s *pcmSource) Done() <-chan struct{} { return s.closed }
+
+// Close detiene ffmpeg y descarta lo que quede por reproducir.
+func (s *pcmSource) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		if s.cmd.Process != nil {
+			_ = s.cmd.Process.Kill()
+		}
+	})
+}
+
+// fade programa un fundido lineal hasta target en n frames.
+func (s *pcmSource) fade(target float64, n int) {
+	s.gainTarget = target
+	if n <= 0 {
+		s.gain, s.fadeLeft = target, 0
+		return
+	}
+	s.gainStep = (target - s.gain) / float64(n)
+	s.fadeLeft = n
+}
+
+// --- mixer ---
+
+// pendingSource es una fuente que espera su turno en el mixer.
+type pendingSource struct {
+	src  *pcmSource
+	fade int // frames de crossfade; 0 = empalme sin hueco
+}
+
+// mixer suma las fuentes activas en un único flujo PCM.
+//
+// Una fuente nueva no suena hasta que le toca: sin crossfade empieza justo
+// cuando terminan las activas (reproducción sin huecos); con crossfade
+// empieza cuando a las activas les queda menos que el fundido, y ambas se
+// mezclan mientras una baja y la otra sube.
+type mixer struct {
+	out  chan []int16
+	wake chan struct{}
+
+	mu      sync.Mutex
+	active  []*pcmSource
+	pending []pendingSource
+	paused  bool
+	volume  float64
+}
+
+func newMixer() *mixer {
+	return &mixer{
+		out:    make(chan []int16, 2),
+		wake:   make(chan struct{}, 1),
+		volume: 1,
+	}
+}
+
+// SetVolume fija el volumen general (1 = 100%).
+func (m *mixer) SetVolume(volume float64) {
+	m.mu.Lock()
+	m.volume = max(volume, 0)
+	m.mu.Unlock()
+}
+
+// Add encola una fuente. crossfade es la duración del fundido con la
+// fuente anterior (0 para empalmarlas sin hueco).
+func (m *mixer) Add(src *pcmSource, crossfade time.Duration) {
+	m.mu.Lock()
+	m.pending = append(m.pending, pendingSource{src: src, fade: int(crossfade / frameDuration)})
+	m.mu.Unlock()
+	m.notify()
+}
+
+// Clear descarta todas las fuentes, activas y pendientes.
+func (m *mixer) Clear() {
+	m.mu.Lock()
+	for _, src := range m.active {
+		src.Close()
+	}
+	for _, p := range m.pending {
+		p.src.Close()
+	}
+	m.active, m.pending = nil, nil
+	m.mu.Unlock()
+}
+
+// Replace sustituye una fuente por otra conservando su turno y su fundido.
+// Se usa para cambiar filtros o saltar a otra posición sin cortar la mezcla.
+// Si old ya no está o se cerró (terminó o se saltó mientras se preparaba
+// src), cierra src y devuelve false.
+func (m *mixer) Replace(old, src *pcmSource) bool {
+	replaced := false
+	m.mu.Lock()
+	if len(m.alive([]*pcmSource{old})) == 0 {
+		m.mu.Unlock()
+		src.Close()
+		return false
+	}
+	for idx, a := range m.active {
+		if a == old {
+			src.gain, src.gainStep, src.gainTarget, src.fadeLeft = a.gain, a.gainStep, a.gainTarget, a.fadeLeft
+			m.active[idx] = src
+			replaced = true
+		}
+	}
+	for idx, p := range m.pending {
+		if p.src == old {
+			m.pending[idx].src = src
+			replaced = true
+		}
+	}
+	m.mu.Unlock()
+	if !replaced {
+		src.Close()
+		return false
+	}
+	old.Close()
+	m.notify()
+	return true
+}
+
+// SetPaused pausa o reanuda la mezcla sin perder la posición.
+func (m *mixer) SetPaused(paused bool) {
+	m.mu.Lock()
+	m.paused = paused
+	m.mu.Unlock()
+	m.notify()
+}
+
+func (m *mixer) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run produce frames mezclados en m.out hasta que se cierre stop.
+func (m *mixer) run(stop <-chan struct{}) {
+	for {
+		m.mu.Lock()
+		m.promote()
+		srcs := append([]*pcmSource(nil), m.active...)
+		paused := m.paused
+		m.mu.Unlock()
+
+		if paused || len(srcs) == 0 {
+			select {
+			case <-m.wake:
+			case <-stop:
+				return
+			}
+			continue
+		}
+
+		// Leer fuera del lock: ffmpeg puede tardar en dar el siguiente frame
+		frames := make([][]int16, len(srcs))
+		for idx, src := range srcs {
+			select {
+			case frame, ok := <-src.frames:
+				if ok {
+					frames[idx] = frame
+				}
+			case <-src.closed:
+			}
+		}
+
+		mixed := make([]int32, frameSize*channels)
+		got := false
+		m.mu.Lock()
+		for idx, src := range srcs {
+			frame := frames[idx]
+			if frame == nil {
+				src.Close()
+				continue
+			}
+			got = true
+			src.played.Add(1)
+			for n, sample := range frame {
+				mixed[n] += int32(float64(sample) * src.gain * m.volume)
+			}
+			if src.fadeLeft > 0 {
+				src.gain += src.gainStep
+				src.fadeLeft--
+				if src.fadeLeft == 0 {
+					src.gain = src.gainTarget
+				}
+				if src.gain <= 0 {
+					src.Close() // terminó de desvanecerse
+				}
+			}
+		}
+		m.active = m.alive(m.active)
+		m.mu.Unlock()
+
+		// Si todas terminaron, la siguiente entra ya sin meter un frame de silencio
+		if !got {
+			continue
+		}
+
+		out := make([]int16, len(mixed))
+		for n, v := range mixed {
+			out[n] = int16(min(max(v, -32768), 32767))
+		}
+		select {
+		case m.out <- out:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// promote pasa a activa la siguiente fuente pendiente cuando le toca;
+// requiere m.mu tomado.
+func (m *mixer) promote() {
+	m.active = m.alive(m.active)
+	if len(m.pending) == 0 {
+		return
+	}
+	next := m.pending[0]
+	select {
+	case <-next.src.closed:
+		m.pending = m.pending[1:]
+		return
+	default:
+	}
+
+	if len(m.active) > 0 {
+		if next.fade == 0 {
+			return
+		}
+		for _, src := range m.active {
+			if left := src.Remaining(); left < 0 || left > time.Duration(next.fade)*frameDuration {
+				return
+			}
+		}
+		// Crossfade: las activas bajan en lo que les queda, la nueva sube
+		for _, src := range m.active {
+			src.fade(0, int(src.Remaining()/frameDuration))
+		}
+		next.src.gain = 0
+		next.src.fade(1, next.fade)
+	}
+	m.active = append(m.active, next.src)
+	m.pending = m.pending[1:]
+}
+
+// alive filtra las fuentes ya cerradas.
+func (m *mixer) alive(srcs []*pcmSource) []*pcmSource {
+	out := srcs[:0]
+	for _, src := range srcs {
+		select {
+		case <-src.closed:
+		default:
+			out = append(out, src)
+		}
+	}
+	return out
+}
+
+// --- Emisor opus ---
+
+// opusSender codifica los frames del mixer y los envía a la conexión de
+// voz actual. Si no hay conexión lista los frames se descartan al ritmo
+// de reproducción, para que la posición siga avanzando.
+func opusSender(in <-chan []int16, voice func() *discordgo.VoiceConnection, health *voiceHealth, stop <-chan struct{}) error {
+	enc, err := gopus.NewEncoder(frameRate, channels, gopus.Audio)
+	if err != nil {
+		return fmt.Errorf("opus encoder error: %w", err)
+	}
+	for {
+		var frame []int16
+		select {
+		case frame = <-in:
+		case <-stop:
+			return nil
+		}
+		health.frame()
+
+		vc := voice()
+		if !voiceReady(vc) {
+			time.Sleep(frameDuration)
+			continue
+		}
+		opus, err := enc.Encode(frame, frameSize, maxOpusBytes)
+		if err != nil {
+			return fmt.Errorf("opus encode error: %w", err)
+		}
+		select {
+		case vc.OpusSend <- opus:
+			health.sent()
+		case <-time.After(time.Second):
+			// discordgo no está consumiendo; se descarta el frame
+		case <-stop:
+			return nil
+		}
+	}
+}
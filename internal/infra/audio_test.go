@@ -0,0 +1,65 @@
+package infra
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func testSource() *pcmSource {
+	return &pcmSource{cmd: &exec.Cmd{}, closed: make(chan struct{}), gain: 1}
+}
+
+func isClosed(src *pcmSource) bool {
+	select {
+	case <-src.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestMixerReplace(t *testing.T) {
+	t.Run("active", func(t *testing.T) {
+		m := newMixer()
+		old, src := testSource(), testSource()
+		old.gain = 0.5
+		m.active = []*pcmSource{old}
+		if !m.Replace(old, src) {
+			t.Fatal("Replace = false")
+		}
+		if m.active[0] != src || src.gain != 0.5 {
+			t.Errorf("active = %v, gain %v", m.active, src.gain)
+		}
+		if !isClosed(old) || isClosed(src) {
+			t.Errorf("old cerrada = %v, src cerrada = %v", isClosed(old), isClosed(src))
+		}
+	})
+	t.Run("pending", func(t *testing.T) {
+		m := newMixer()
+		old, src := testSource(), testSource()
+		m.pending = []pendingSource{{src: old, fade: 3}}
+		if !m.Replace(old, src) || m.pending[0].src != src || m.pending[0].fade != 3 {
+			t.Errorf("pending = %+v", m.pending)
+		}
+	})
+	// La canción terminó o se saltó mientras se preparaba src
+	t.Run("gone", func(t *testing.T) {
+		m := newMixer()
+		old, src := testSource(), testSource()
+		if m.Replace(old, src) {
+			t.Error("Replace = true sin old en la mezcla")
+		}
+		if !isClosed(src) || isClosed(old) {
+			t.Errorf("old cerrada = %v, src cerrada = %v", isClosed(old), isClosed(src))
+		}
+	})
+	t.Run("closed", func(t *testing.T) {
+		m := newMixer()
+		old, src := testSource(), testSource()
+		m.active = []*pcmSource{old}
+		old.Close()
+		if m.Replace(old, src) || m.active[0] != old || !isClosed(src) {
+			t.Errorf("Replace sobre una fuente cerrada: active = %v, src cerrada = %v", m.active, isClosed(src))
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package infra
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"feints/internal/core"
+)
+
+// relatedLimit es cuántas canciones relacionadas se piden a yt-dlp por consulta.
+const relatedLimit = 10
+
+var errNoAutoplayCandidates = errors.New("autoplay: no hay canciones candidatas")
+
+// AutoplayEngine elige la siguiente canción cuando la cola se queda vacía.
+//
+// Con la fuente "smart" combina dos orígenes: "descubrimiento" (canciones
+// relacionadas con la última URL reproducida, vía el mix de YouTube) y
+// "familiar" (biblioteca local, puntuada según el artista y el género de lo
+// que se ha escuchado). La proporción entre ambas la fija discoveryRatio.
+// El resto de fuentes (ver core.AutoplaySource) restringen el origen.
+// Las canciones del historial reciente nunca se repiten.
+type AutoplayEngine struct {
+	guildID        string
+	discoveryRatio float64
+	historySize    int
+	// library y related son los dos orígenes: la biblioteca local y las
+	// relacionadas de yt-dlp (Related)
+	library func() []core.Song
+	related func(url string, limit int) ([]core.Song, error)
+
+	mu      sync.Mutex
+	source  core.AutoplaySource
+	value   string
+	history []core.Song // la más reciente al final
+	played  map[string]int
+	rng     *rand.Rand
+}
+
+// NewAutoplayEngine crea un motor de autoplay para un guild.
+func NewAutoplayEngine(service *SongService, guildID string, discoveryRatio float64, historySize int) *AutoplayEngine {
+	if historySize <= 0 {
+		historySize = 1
+	}
+	return &AutoplayEngine{
+		guildID:        guildID,
+		source:         core.AutoplaySmart,
+		discoveryRatio: discoveryRatio,
+		historySize:    historySize,
+		library:        service.LocalSongs,
+		related:        Related,
+		played:         make(map[string]int),
+		rng:            rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// Record apunta una canción reproducida en el historial.
+func (e *AutoplayEngine) Record(song core.Song) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.history = append(e.history, song)
+	if len(e.history) > e.historySize {
+		e.history = e.history[len(e.history)-e.historySize:]
+	}
+	e.played[song.Key()]++
+}
+
+// SetSource cambia la fuente del autoplay. value es el nombre de la
+// playlist o el género, según la fuente.
+func (e *AutoplayEngine) SetSource(source core.AutoplaySource, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !source.Valid() {
+		source = core.AutoplaySmart
+	}
+	e.source, e.value = source, value
+}
+
+type autoplaySourceFunc func(recent []core.Song) ([]core.Song, error)
+
+// Next devuelve la siguiente canción a reproducir automáticamente. Puede
+// tardar varios segundos (Related consulta yt-dlp).
+func (e *AutoplayEngine) Next() (*core.Song, error) {
+	e.mu.Lock()
+	recent := append([]core.Song(nil), e.history...)
+	source, value := e.source, e.value
+	discovery := e.rng.Float64() < e.discoveryRatio
+	e.mu.Unlock()
+
+	var sources []autoplaySourceFunc
+	switch source {
+	case core.AutoplayLocal:
+		sources = []autoplaySourceFunc{e.local}
+	case core.AutoplayRelated:
+		sources = []autoplaySourceFunc{e.discovery}
+	case core.AutoplayGenre:
+		sources = []autoplaySourceFunc{func(recent []core.Song) ([]core.Song, error) {
+			return e.genre(recent, value)
+		}}
+	case core.AutoplayPlaylist:
+		sources = []autoplaySourceFunc{func(recent []core.Song) ([]core.Song, error) {
+			return e.playlist(recent, value)
+		}}
+	default:
+		sources = []autoplaySourceFunc{e.familiar, e.discovery}
+		if discovery {
+			sources[0], sources[1] = sources[1], sources[0]
+		}
+	}
+
+	// Si una fuente no tiene candidatos se prueba la siguiente
+	err := errNoAutoplayCandidates
+	for _, source := range sources {
+		songs, serr := source(recent)
+		if serr != nil {
+			err = serr
+			continue
+		}
+		if song := e.pick(songs); song != nil {
+			return song, nil
+		}
+	}
+	return nil, err
+}
+
+// local devuelve la biblioteca local sin las canciones recientes.
+func (e *AutoplayEngine) local(recent []core.Song) ([]core.Song, error) {
+	return withoutRecent(e.library(), recent), nil
+}
+
+// genre devuelve las canciones locales del género pedido.
+func (e *AutoplayEngine) genre(recent []core.Song, genre string) ([]core.Song, error) {
+	var songs []core.Song
+	for _, s := range e.library() {
+		if strings.EqualFold(s.Genre, genre) {
+			songs = append(songs, s)
+		}
+	}
+	return withoutRecent(songs, recent), nil
+}
+
+// playlist devuelve las canciones de una playlist guardada. Si todas se han
+// oído hace poco se vuelve a empezar por la playlist completa.
+func (e *AutoplayEngine) playlist(recent []core.Song, name string) ([]core.Song, error) {
+	songs, err := LoadPlaylist(e.guildID, name)
+	if err != nil {
+		return nil, err
+	}
+	if fresh := withoutRecent(songs, recent); len(fresh) > 0 {
+		return fresh, nil
+	}
+	return songs, nil
+}
+
+// discovery devuelve canciones relacionadas con la última URL reproducida.
+func (e *AutoplayEngine) discovery(recent []core.Song) ([]core.Song, error) {
+	var err error
+	for i := len(recent) - 1; i >= 0; i-- {
+		if recent[i].URL == "" {
+			continue
+		}
+		var songs []core.Song
+		songs, err = e.related(recent[i].URL, relatedLimit)
+		if err != nil {
+			continue
+		}
+		return withoutRecent(songs, recent), nil
+	}
+	return nil, err
+}
+
+// familiar devuelve la biblioteca local ordenada por afinidad con lo reciente.
+// Cada canción aparece tantas veces como su puntuación, de modo que pick
+// favorece a las más afines sin descartar del todo al resto.
+func (e *AutoplayEngine) familiar(recent []core.Song) ([]core.Song, error) {
+	uploaders := make(map[string]bool)
+	genres := make(map[string]bool)
+	for _, s := range recent {
+		if s.Uploader != "" {
+			uploaders[strings.ToLower(s.Uploader)] = true
+		}
+		if s.Genre != "" {
+			genres[strings.ToLower(s.Genre)] = true
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var weighted []core.Song
+	for _, s := range withoutRecent(e.library(), recent) {
+		score := 1
+		if uploaders[strings.ToLower(s.Uploader)] {
+			score += 3
+		}
+		if genres[strings.ToLower(s.Genre)] {
+			score += 2
+		}
+		if e.played[s.Key()] > 0 {
+			score++
+		}
+		for range score {
+			weighted = append(weighted, s)
+		}
+	}
+	return weighted, nil
+}
+
+func (e *AutoplayEngine) pick(songs []core.Song) *core.Song {
+	if len(songs) == 0 {
+		return nil
+	}
+	e.mu.Lock()
+	s := songs[e.rng.Intn(len(songs))]
+	e.mu.Unlock()
+	return &s
+}
+
+// withoutRecent filtra las canciones que están en el historial reciente.
+func withoutRecent(songs, recent []core.Song) []core.Song {
+	seen := make(map[string]bool, len(recent))
+	for _, s := range recent {
+		seen[s.Key()] = true
+	}
+	var out []core.Song
+	for _, s := range songs {
+		if s.Key() == "" || seen[s.Key()] {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,189 @@
+package infra
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"feints/internal/core"
+)
+
+// testEngine crea un motor con la biblioteca y las relacionadas de prueba
+// en lugar de la caché y yt-dlp.
+func testEngine(ratio float64, historySize int, library []core.Song, related func(url string) ([]core.Song, error)) *AutoplayEngine {
+	e := NewAutoplayEngine(nil, "g1", ratio, historySize)
+	e.library = func() []core.Song { return library }
+	e.related = func(url string, _ int) ([]core.Song, error) { return related(url) }
+	return e
+}
+
+// picks llama a Next varias veces y devuelve las claves elegidas sin repetir.
+func picks(t *testing.T, e *AutoplayEngine) ([]string, error) {
+	t.Helper()
+	var keys []string
+	for range 30 {
+		song, err := e.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(keys, song.Key()) {
+			keys = append(keys, song.Key())
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func TestAutoplaySources(t *testing.T) {
+	t.Chdir(t.TempDir())
+	library := []core.Song{
+		{Title: "rock", URL: "local-rock", Genre: "rock"},
+		{Title: "jazz", URL: "local-jazz", Genre: "Jazz"},
+	}
+	if err := SavePlaylist("g1", "chill", []core.Song{{URL: "list-1"}, {URL: "list-2"}}); err != nil {
+		t.Fatal(err)
+	}
+	errYtdlp := errors.New("yt-dlp falló")
+	related := func(url string) ([]core.Song, error) {
+		if url != "seed" {
+			return nil, errYtdlp
+		}
+		return []core.Song{{URL: "related-1"}}, nil
+	}
+	failing := func(string) ([]core.Song, error) { return nil, errYtdlp }
+
+	tests := []struct {
+		name    string
+		source  core.AutoplaySource
+		value   string
+		ratio   float64
+		related func(url string) ([]core.Song, error)
+		want    []string
+		wantErr error
+	}{
+		{name: "local", source: core.AutoplayLocal, related: related, want: []string{"local-jazz", "local-rock"}},
+		{name: "related", source: core.AutoplayRelated, related: related, want: []string{"related-1"}},
+		{name: "related failing", source: core.AutoplayRelated, related: failing, wantErr: errYtdlp},
+		{name: "genre", source: core.AutoplayGenre, value: "jazz", related: related, want: []string{"local-jazz"}},
+		{name: "genre without songs", source: core.AutoplayGenre, value: "metal", related: related, wantErr: errNoAutoplayCandidates},
+		{name: "playlist", source: core.AutoplayPlaylist, value: "chill", related: related, want: []string{"list-1", "list-2"}},
+		{name: "smart discovery first", source: core.AutoplaySmart, ratio: 1, related: related, want: []string{"related-1"}},
+		{name: "smart familiar first", source: core.AutoplaySmart, ratio: 0, related: related, want: []string{"local-jazz", "local-rock"}},
+		// Sin relacionadas se pasa a la biblioteca
+		{name: "smart falls back", source: core.AutoplaySmart, ratio: 1, related: failing, want: []string{"local-jazz", "local-rock"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testEngine(tt.ratio, 20, library, tt.related)
+			e.SetSource(tt.source, tt.value)
+			e.Record(core.Song{URL: "seed"})
+			got, err := picks(t, e)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Next() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Errorf("Next() eligió %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoplayAvoidsRecent(t *testing.T) {
+	t.Chdir(t.TempDir())
+	library := []core.Song{{URL: "a"}, {URL: "b"}, {URL: "c"}}
+	noRelated := func(string) ([]core.Song, error) { return nil, errors.New("sin relacionadas") }
+	song := func(key string) core.Song { return core.Song{URL: key} }
+
+	tests := []struct {
+		name        string
+		source      core.AutoplaySource
+		historySize int
+		played      []string
+		related     func(url string) ([]core.Song, error)
+		want        []string
+		wantErr     bool
+	}{
+		{name: "skips recent", source: core.AutoplayLocal, historySize: 20, played: []string{"a", "b"}, related: noRelated, want: []string{"c"}},
+		// Con historial de 2, "a" ya no es reciente
+		{name: "history size", source: core.AutoplayLocal, historySize: 2, played: []string{"a", "b", "c"}, related: noRelated, want: []string{"a"}},
+		{name: "all recent", source: core.AutoplayLocal, historySize: 20, played: []string{"a", "b", "c"}, related: noRelated, wantErr: true},
+		{name: "related skips recent", source: core.AutoplayRelated, historySize: 20, played: []string{"a"},
+			related: func(string) ([]core.Song, error) { return []core.Song{song("a"), song("z")}, nil }, want: []string{"z"}},
+		// Una playlist oída entera vuelve a empezar
+		{name: "playlist restarts", source: core.AutoplayPlaylist, historySize: 20, played: []string{"a", "b", "c"}, related: noRelated, want: []string{"a", "b", "c"}},
+	}
+	if err := SavePlaylist("g1", "all", library); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testEngine(0, tt.historySize, library, tt.related)
+			e.SetSource(tt.source, "all")
+			for _, key := range tt.played {
+				e.Record(song(key))
+			}
+			got, err := picks(t, e)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Next() eligió %v, want error", got)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Errorf("Next() eligió %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestAutoplayDoesNotBlockControl comprueba que las órdenes se atienden
+// mientras el autoplay elige canción.
+func TestAutoplayDoesNotBlockControl(t *testing.T) {
+	release := make(chan struct{})
+	p := &DgvoicePlayer{
+		Logger:  slog.New(slog.DiscardHandler),
+		Control: make(chan controlCmd),
+		state:   Idle,
+		mixer:   newMixer(),
+		doneCh:  make(chan *track),
+		autoCh:  make(chan autoplayPick),
+		quit:    make(chan struct{}),
+		engine: testEngine(1, 20, nil, func(string) ([]core.Song, error) {
+			<-release
+			return nil, errors.New("yt-dlp falló")
+		}),
+	}
+	p.SetQueueStrategy(core.LookupQueueStrategy("fifo"))
+	p.SetAutoPlay(core.AutoplaySettings{Enabled: true, Source: core.AutoplayRelated})
+	p.engine.Record(core.Song{URL: "seed"})
+	go p.stateLoop()
+	defer close(p.quit)
+
+	// La primera vuelta del bucle ya está eligiendo
+	time.Sleep(300 * time.Millisecond)
+	sent := make(chan struct{})
+	go func() {
+		p.Pause()
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("Pause se bloqueó mientras el autoplay elegía")
+	}
+
+	// Si falla la elección, el autoplay se desactiva
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for p.AutoPlaySettings().Enabled {
+		if time.Now().After(deadline) {
+			t.Fatal("el autoplay sigue activo tras fallar la elección")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -49,6 +49,7 @@ func PreloadSongCache(c *SongCache) error {
 		// Extraer metadata principal
 		title := tag.Title()
 		uploader := tag.Artist()
+		genre := tag.Genre()
 		length := tag.GetTextFrame(tag.CommonID("Length")).Text
 
 		// Buscar frames TXXX (pueden contener URL u otros datos)
@@ -77,6 +78,7 @@ func PreloadSongCache(c *SongCache) error {
 			Duration: dur,
 			Path:     path,
 			URL:      url,
+			Genre:    genre,
 		}
 		c.AddSong(s)
 	}
@@ -109,6 +111,17 @@ func (c *SongCache) GetSong(url string) *core.Song {
 	return c.songs[url]
 }
 
+// Songs devuelve una copia de todas las canciones de la biblioteca local.
+func (c *SongCache) Songs() []core.Song {
+	c.muSongs.RLock()
+	defer c.muSongs.RUnlock()
+	songs := make([]core.Song, 0, len(c.songs))
+	for _, s := range c.songs {
+		songs = append(songs, *s)
+	}
+	return songs
+}
+
 func (c *SongCache) AddSearch(query string, results []core.Song) {
 	c.muSearch.Lock()
 	defer c.muSearch.Unlock()
@@ -1,11 +1,15 @@
 package infra
 
 import (
+	"feints/config"
 	"feints/internal/core"
 	"log/slog"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/bwmarrin/dgvoice"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -19,19 +23,45 @@ const (
 	Stopped PlayerState = "stopped"
 )
 
+// handoffLead es cuánto antes del final (además del crossfade) se prepara la
+// siguiente canción, para que empiece sin hueco aunque haya que decodificarla.
+const handoffLead = 5 * time.Second
+
 type DgvoicePlayer struct {
 	Session   *discordgo.Session `json:"-"`
 	GuildID   string             `json:"guild_id"`
 	ChannelID string             `json:"channel_id"`
-	queueA    []*core.Song
-	Queue     chan core.Song  `json:"-"`
+	queueMu   sync.Mutex
+	queue     []*core.Song // en orden de llegada
+	strategy  core.QueueStrategy
+	turns     core.Turns
 	Control   chan controlCmd `json:"-"`
 	state     PlayerState
 	Logger    *slog.Logger `json:"-"`
+	vcMu      sync.Mutex
 	vc        *discordgo.VoiceConnection
-	stopCh    chan bool
-	doneCh    chan bool
-	autoplay  bool
+	joinMu    sync.Mutex
+	health    voiceHealth
+	onFailure func(err error)
+	onSongErr func(song core.Song, err error)
+	mixer     *mixer
+	curMu     sync.Mutex
+	current   *track
+	doneCh    chan *track
+	// autoCh trae la canción elegida por el autoplay; choosing indica que
+	// se está eligiendo una (sólo lo toca stateLoop)
+	autoCh    chan autoplayPick
+	choosing  bool
+	quit      chan struct{}
+	closeOnce sync.Once
+	autoMu    sync.Mutex
+	autoplay  core.AutoplaySettings
+	engine    *AutoplayEngine
+	filtersMu sync.Mutex
+	filters   core.FilterChain
+	votesMu   sync.Mutex
+	voteTrack *track
+	votes     map[string]bool
 }
 
 type controlCmd string
@@ -42,121 +72,368 @@ const (
 	cmdResume controlCmd = "resume"
 	cmdNext   controlCmd = "next"
 	cmdStop   controlCmd = "stop"
+	// cmdRefilter vuelve a decodificar la canción actual con los filtros nuevos
+	cmdRefilter controlCmd = "refilter"
 )
 
+// track es una canción en reproducción; cancel se cierra al saltarla o
+// pararla, refilter avisa de que han cambiado los filtros y seek pide
+// saltar a otra posición.
+type track struct {
+	song     core.Song
+	cancel   chan struct{}
+	refilter chan struct{}
+	seek     chan time.Duration
+	once     sync.Once
+
+	// Lo rellena playSong cuando la canción empieza a sonar
+	playing atomic.Pointer[core.Song]
+	src     atomic.Pointer[pcmSource]
+}
+
+func newTrack(song core.Song) *track {
+	return &track{
+		song:     song,
+		cancel:   make(chan struct{}),
+		refilter: make(chan struct{}, 1),
+		seek:     make(chan time.Duration, 1),
+	}
+}
+
+func (t *track) stop() { t.once.Do(func() { close(t.cancel) }) }
+
+// autoplayPick es el resultado de AutoplayEngine.Next.
+type autoplayPick struct {
+	song *core.Song
+	err  error
+}
+
 // NewDgvoicePlayer devuelve un Player
 func NewDgvoicePlayer(session *discordgo.Session, guildID, channelID string, l *slog.Logger) core.Player {
 	p := &DgvoicePlayer{
 		Session:   session,
 		GuildID:   guildID,
 		ChannelID: channelID,
-		Queue:     make(chan core.Song, 50),
 		Control:   make(chan controlCmd),
 		state:     Idle,
 		Logger:    l.With("component", "Player", "guild", guildID),
-		stopCh:    make(chan bool),
-		doneCh:    make(chan bool),
+		mixer:     newMixer(),
+		doneCh:    make(chan *track),
+		autoCh:    make(chan autoplayPick),
+		quit:      make(chan struct{}),
+		engine: NewAutoplayEngine(GlobalSongService, guildID,
+			config.Global.AutoplayDiscoveryRatio, config.Global.AutoplayHistorySize),
 	}
+	settings := GlobalSettings.Get(guildID)
+	p.filters = settings.Filters() // sin bucle aún: SetFilters se bloquearía
+	p.SetAutoPlay(settings.Autoplay())
+	p.SetQueueStrategy(core.LookupQueueStrategy(settings.String(core.SettingQueueStrategy)))
+	p.mixer.SetVolume(float64(settings.Int(core.SettingDefaultVolume)) / 100)
 	go p.stateLoop()
+	go p.mixer.run(p.quit)
+	go p.superviseVoice()
+	go func() {
+		if err := opusSender(p.mixer.out, p.voice, &p.health, p.quit); err != nil {
+			p.Logger.Error("opus sender stopped", "error", err)
+		}
+	}()
 	return p
 }
 
 // --- Interface methods ---
-func (p *DgvoicePlayer) Play()     { p.Control <- cmdPlay }
-func (p *DgvoicePlayer) Next()     { p.Control <- cmdNext }
-func (p *DgvoicePlayer) Pause()    { p.Control <- cmdPause }
-func (p *DgvoicePlayer) Resume()   { p.Control <- cmdResume }
-func (p *DgvoicePlayer) Stop()     { p.Control <- cmdStop }
-func (p *DgvoicePlayer) AutoPlay() { p.autoplay = true }
+func (p *DgvoicePlayer) Play()   { p.send(cmdPlay) }
+func (p *DgvoicePlayer) Next()   { p.send(cmdNext) }
+func (p *DgvoicePlayer) Pause()  { p.send(cmdPause) }
+func (p *DgvoicePlayer) Resume() { p.send(cmdResume) }
+func (p *DgvoicePlayer) Stop()   { p.send(cmdStop) }
+
+// send entrega una orden al bucle central; con el player cerrado se descarta
+// en lugar de bloquear a quien la manda.
+func (p *DgvoicePlayer) send(cmd controlCmd) {
+	select {
+	case p.Control <- cmd:
+	case <-p.quit:
+	}
+}
+
+// Close para la reproducción, vacía la cola, suelta la conexión de voz y
+// termina las goroutines del player. Se puede llamar más de una vez.
+func (p *DgvoicePlayer) Close() {
+	p.closeOnce.Do(func() {
+		p.Stop()
+		close(p.quit)
+		p.Logger.Info("Player closed")
+	})
+}
+
+// Shuffle mezcla las canciones pendientes.
+func (p *DgvoicePlayer) Shuffle() {
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	rand.Shuffle(len(p.queue), func(a, b int) { p.queue[a], p.queue[b] = p.queue[b], p.queue[a] })
+	p.Logger.Info("Queue shuffled", "songs", len(p.queue))
+}
+
+// SetAutoPlay activa o desactiva el autoplay y cambia su fuente.
+func (p *DgvoicePlayer) SetAutoPlay(settings core.AutoplaySettings) {
+	p.engine.SetSource(settings.Source, settings.Value)
+	p.autoMu.Lock()
+	p.autoplay = settings
+	p.autoMu.Unlock()
+}
+
+// AutoPlaySettings devuelve la configuración de autoplay activa.
+func (p *DgvoicePlayer) AutoPlaySettings() core.AutoplaySettings {
+	p.autoMu.Lock()
+	defer p.autoMu.Unlock()
+	return p.autoplay
+}
+
+// disableAutoPlay apaga el autoplay sin olvidar su fuente.
+func (p *DgvoicePlayer) disableAutoPlay() {
+	p.autoMu.Lock()
+	p.autoplay.Enabled = false
+	p.autoMu.Unlock()
+}
+
+// SetFilters cambia los efectos de audio. Si hay una canción sonando se
+// vuelve a decodificar desde la posición actual con los nuevos filtros.
+func (p *DgvoicePlayer) SetFilters(f core.FilterChain) {
+	p.filtersMu.Lock()
+	p.filters = f
+	p.filtersMu.Unlock()
+	p.send(cmdRefilter)
+}
+
+// Filters devuelve los efectos de audio activos.
+func (p *DgvoicePlayer) Filters() core.FilterChain {
+	p.filtersMu.Lock()
+	defer p.filtersMu.Unlock()
+	return p.filters
+}
+
+// sourceOptions devuelve las opciones de decodificación con los filtros activos.
+func (p *DgvoicePlayer) sourceOptions(song core.Song, start time.Duration) sourceOptions {
+	f := p.Filters()
+	return sourceOptions{
+		Start:   start,
+		End:     song.End,
+		Length:  song.Start + song.Length(),
+		Filters: ffmpegFilters(f),
+		Tempo:   f.Tempo(),
+	}
+}
 
 func (p *DgvoicePlayer) AddSong(song core.Song) {
-	p.Logger.Info("Queueing song", "title", song.Title)
-	p.Queue <- song
-	p.queueA = append(p.queueA, &song)
+	p.Logger.Info("Queueing song", "title", song.Title, "requester", song.RequesterName)
+	p.queueMu.Lock()
+	p.queue = append(p.queue, &song)
+	p.queueMu.Unlock()
 }
 
+// ListQueue devuelve las canciones pendientes en el orden en que sonarán.
 func (p *DgvoicePlayer) ListQueue() []*core.Song {
-	snapshot := make([]*core.Song, len(p.queueA))
-	copy(snapshot, p.queueA)
-	return snapshot
+	p.queueMu.Lock()
+	defer p.queueMu.Unlock()
+	return p.strategy.Order(p.queue, p.turns)
+}
+
+// SetQueueStrategy cambia el orden de la cola; se aplica también a lo ya encolado.
+func (p *DgvoicePlayer) SetQueueStrategy(strategy core.QueueStrategy) {
+	p.queueMu.Lock()
+	p.strategy = strategy
+	p.queueMu.Unlock()
 }
 
 func (p *DgvoicePlayer) State() string { return string(p.state) }
 
+// Current devuelve la canción que está sonando y su posición dentro del
+// fichero, o nil si no suena nada.
+func (p *DgvoicePlayer) Current() (*core.Song, time.Duration) {
+	t := p.currentTrack()
+	if t == nil {
+		return nil, 0
+	}
+	song, src := t.playing.Load(), t.src.Load()
+	if song == nil || src == nil {
+		return nil, 0
+	}
+	return song, src.Position()
+}
+
+// Seek salta a una posición (absoluta dentro del fichero) de la canción actual.
+func (p *DgvoicePlayer) Seek(pos time.Duration) bool {
+	t := p.currentTrack()
+	if t == nil {
+		return false
+	}
+	select {
+	case <-t.seek: // descartar un salto pendiente
+	default:
+	}
+	t.seek <- pos
+	return true
+}
+
+// VoteSkip registra un voto para saltar la canción actual. Los votos son
+// de la canción en curso: al cambiar de canción empiezan de cero.
+func (p *DgvoicePlayer) VoteSkip(userID string, required int) (int, bool) {
+	t := p.currentTrack()
+	if t == nil {
+		return 0, false
+	}
+
+	p.votesMu.Lock()
+	if p.voteTrack != t {
+		p.voteTrack, p.votes = t, make(map[string]bool)
+	}
+	p.votes[userID] = true
+	votes := len(p.votes)
+	p.votesMu.Unlock()
+
+	if votes >= required {
+		p.Logger.Info("Vote skip passed", "votes", votes, "required", required)
+		p.Next()
+		return votes, true
+	}
+	return votes, false
+}
+
+func (p *DgvoicePlayer) currentTrack() *track {
+	p.curMu.Lock()
+	defer p.curMu.Unlock()
+	return p.current
+}
+
+// setCurrent cambia la canción actual; sólo lo llama el bucle de estado.
+func (p *DgvoicePlayer) setCurrent(t *track) {
+	p.curMu.Lock()
+	p.current = t
+	p.curMu.Unlock()
+}
+
 // --- Bucle central ---
 func (p *DgvoicePlayer) stateLoop() {
 	p.Logger.Info("State loop started")
 
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case cmd := <-p.Control:
 			p.cmdHandler(cmd)
-		default:
-		}
-		switch p.state {
-		case Idle:
-			select {
-			case song := <-p.Queue:
-				p.Logger.Info("Auto-playing next song", "title", song.Title)
-				p.state = Playing
-				go p.playSong(song)
-			default:
-				if p.autoplay {
-					s, e := GlobalSongService.GetRandomLocalSong()
-					if e != nil {
-						p.Logger.Error("error getting random local song", "error", e)
-						p.Stop()
-						continue
-					}
-					p.AddSong(*s)
+		case t := <-p.doneCh:
+			// Ignorar canciones que ya se saltaron o pararon
+			if t == p.current {
+				p.Logger.Info("Song finished", "title", t.song.Title)
+				p.setCurrent(nil)
+				if p.state == Playing {
+					p.state = Idle
 				}
 			}
+		case pick := <-p.autoCh:
+			p.autoplayPicked(pick)
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
 
-		case Playing:
-			select {
-			case <-p.doneCh:
-				p.Logger.Info("Song finished")
-				p.state = Idle
-			default:
-			}
-		case Paused:
-			time.Sleep(time.Second)
-		case Stopped:
-			time.Sleep(time.Second)
+		if p.state == Idle {
+			p.playNext()
+		}
+	}
+}
+
+// playNext arranca la siguiente canción de la cola o, si está vacía y el
+// autoplay está activo, encola una elegida por el motor de autoplay.
+func (p *DgvoicePlayer) playNext() {
+	p.queueMu.Lock()
+	var next *core.Song
+	if ordered := p.strategy.Order(p.queue, p.turns); len(ordered) > 0 {
+		next = ordered[0]
+		p.turns.Served(next.RequesterID)
+		p.queue = slices.DeleteFunc(p.queue, func(s *core.Song) bool { return s == next })
+	}
+	p.queueMu.Unlock()
 
-		default:
+	if next != nil {
+		p.Logger.Info("Auto-playing next song", "title", next.Title)
+		t := newTrack(*next)
+		p.setCurrent(t)
+		p.state = Playing
+		go p.playSong(t)
+		return
+	}
+
+	if p.choosing || !p.AutoPlaySettings().Enabled {
+		return
+	}
+	// Elegir puede tardar segundos: fuera del bucle, para que las órdenes
+	// (/pause, /skip, /stop, botones) no esperen
+	p.choosing = true
+	go func() {
+		song, err := p.engine.Next()
+		select {
+		case p.autoCh <- autoplayPick{song: song, err: err}:
+		case <-p.quit:
 		}
+	}()
+}
 
+// autoplayPicked encola la canción elegida por el autoplay, salvo que
+// mientras se elegía se haya desactivado (p. ej. con /stop) o ya suene o
+// espere otra.
+func (p *DgvoicePlayer) autoplayPicked(pick autoplayPick) {
+	p.choosing = false
+	if pick.err != nil {
+		// No llamar a p.Stop(): bloquearía este mismo bucle
+		p.Logger.Error("error choosing autoplay song", "error", pick.err)
+		p.disableAutoPlay()
+		return
+	}
+	if !p.AutoPlaySettings().Enabled || p.state != Idle || len(p.ListQueue()) > 0 {
+		return
 	}
+	p.AddSong(*pick.song)
 }
 
 // --- Manejo de comandos ---
 func (p *DgvoicePlayer) cmdHandler(cmd controlCmd) {
 	switch cmd {
 	case cmdPlay:
-		if p.state != Playing {
-			select {
-			case song := <-p.Queue:
-				p.Logger.Info("Auto-playing next song", "title", song.Title)
-				p.state = Playing
-				go p.playSong(song)
-			default:
-			}
-
+		switch p.state {
+		case Idle:
+			p.playNext()
+		case Paused:
+			p.cmdHandler(cmdResume)
 		}
 
 	case cmdPause:
+		if p.current == nil {
+			// Sin canción no hay nada que pausar; quedarse en Paused dejaría
+			// la cola parada (el bucle sólo avanza desde Idle)
+			return
+		}
 		p.state = Paused
 		p.Logger.Info("Paused")
-		if p.vc != nil {
-			p.vc.Speaking(false)
+		p.mixer.SetPaused(true)
+		if vc := p.voice(); vc != nil {
+			vc.Speaking(false)
 		}
 
 	case cmdResume:
+		p.mixer.SetPaused(false)
+		if p.current == nil {
+			// La canción terminó o se paró estando en pausa: seguir con la cola
+			p.state = Idle
+			p.playNext()
+			return
+		}
 		p.state = Playing
 		p.Logger.Info("Resumed")
-		if p.vc != nil {
-			p.vc.Speaking(true)
+		if vc := p.voice(); vc != nil {
+			vc.Speaking(true)
 		}
 
 	case cmdNext:
@@ -167,69 +444,272 @@ func (p *DgvoicePlayer) cmdHandler(cmd controlCmd) {
 	case cmdStop:
 		p.Logger.Info("Stopping and clearing queue")
 		p.stopCurrentPlayback()
-		p.Queue = make(chan core.Song, 50)
-		p.queueA = make([]*core.Song,0)
-		p.autoplay = false
+		p.drainQueue()
+		p.disableAutoPlay()
+		p.disconnect()
 		p.state = Idle
+
+	case cmdRefilter:
+		if p.current != nil {
+			select {
+			case p.current.refilter <- struct{}{}:
+			default:
+			}
+		}
 	}
 }
+
+// stopCurrentPlayback corta la canción actual (y la que pudiera estar
+// entrando con crossfade) sin soltar la conexión de voz.
 func (p *DgvoicePlayer) stopCurrentPlayback() {
-	// Use a non-blocking select to send stop signal
-	select {
-	case p.stopCh <- true:
-	default:
+	if p.current != nil {
+		p.current.stop()
+		p.setCurrent(nil)
 	}
-	if p.vc != nil {
-		p.vc.Speaking(false)
-		p.vc.Disconnect()
-		p.vc = nil
+	p.mixer.Clear()
+	p.mixer.SetPaused(false)
+}
+
+func (p *DgvoicePlayer) drainQueue() {
+	p.queueMu.Lock()
+	p.queue = nil
+	p.turns = core.Turns{}
+	p.queueMu.Unlock()
+}
+
+// --- Conexión de voz ---
+
+func (p *DgvoicePlayer) voice() *discordgo.VoiceConnection {
+	p.vcMu.Lock()
+	defer p.vcMu.Unlock()
+	return p.vc
+}
+
+// VoiceChannel devuelve el canal de voz del player.
+func (p *DgvoicePlayer) VoiceChannel() string {
+	p.vcMu.Lock()
+	defer p.vcMu.Unlock()
+	return p.ChannelID
+}
+
+// MoveTo cambia el canal de voz del player. Con una conexión activa se mueve
+// en el momento (el mixer sigue enviando la canción actual); sin ella sólo se
+// apunta el canal para la próxima canción.
+func (p *DgvoicePlayer) MoveTo(channelID string) error {
+	p.vcMu.Lock()
+	p.ChannelID = channelID
+	vc := p.vc
+	p.vcMu.Unlock()
+	if !voiceReady(vc) {
+		return nil
 	}
+	p.Logger.Info("Moving to voice channel", "channelID", channelID)
+	return p.ensureVoice()
 }
 
-// --- Reproducir canción ---
-func (p *DgvoicePlayer) playSong(song core.Song) {
-	var err error
+// voiceReady indica si la conexión puede enviar audio.
+func voiceReady(vc *discordgo.VoiceConnection) bool {
+	if vc == nil {
+		return false
+	}
+	vc.RLock()
+	defer vc.RUnlock()
+	return vc.Ready && vc.OpusSend != nil
+}
 
-	if len(p.queueA) > 0 {
-		p.queueA = p.queueA[1:]
-	} else {
-		p.Logger.Debug("no more songs in queue")
+// ensureVoice se une al canal si no hay conexión. La conexión se mantiene
+// entre canciones para evitar cortes y sonidos de entrada.
+func (p *DgvoicePlayer) ensureVoice() error {
+	// Lo llaman playSong, MoveTo y el supervisor: uno cada vez
+	p.joinMu.Lock()
+	defer p.joinMu.Unlock()
+
+	channelID := p.VoiceChannel()
+	if vc := p.voice(); voiceReady(vc) && vc.ChannelID == channelID {
+		return nil
+	}
+	vc, err := p.joinVoice(channelID)
+	if err != nil {
+		return err
+	}
+	vc.Speaking(true)
+
+	p.vcMu.Lock()
+	p.vc = vc
+	p.vcMu.Unlock()
+	return nil
+}
+
+func (p *DgvoicePlayer) disconnect() {
+	p.vcMu.Lock()
+	vc := p.vc
+	p.vc = nil
+	p.vcMu.Unlock()
+	if vc != nil {
+		vc.Speaking(false)
+		vc.Disconnect()
+	}
+}
+
+// OnSongFailure registra fn para cuando una canción no se pueda obtener y
+// se salte.
+func (p *DgvoicePlayer) OnSongFailure(fn func(song core.Song, err error)) {
+	p.vcMu.Lock()
+	defer p.vcMu.Unlock()
+	p.onSongErr = fn
+}
+
+// songFailed avisa de que song no se pudo obtener.
+func (p *DgvoicePlayer) songFailed(song core.Song, err error) {
+	p.vcMu.Lock()
+	fn := p.onSongErr
+	p.vcMu.Unlock()
+	if fn != nil {
+		fn(song, err)
 	}
-	s:= GlobalSongService.cache.GetSong(song.URL)
-	if s != nil {
+}
+
+// --- Reproducir canción ---
+
+// playSong descarga (si hace falta) y reproduce una canción. Avisa por
+// doneCh cuando termina o, si la duración es conocida, un poco antes del
+// final para que la siguiente entre sin hueco o con crossfade; el mixer
+// sigue reproduciendo lo que quede de ésta.
+func (p *DgvoicePlayer) playSong(t *track) {
+	defer func() {
+		select {
+		case p.doneCh <- t:
+		case <-p.quit:
+		}
+	}()
+
+	song := t.song
+	if s := GlobalSongService.cache.GetSong(song.URL); s != nil && s.Path != "" {
 		song = *s
 	} else {
-
-		s, err := GlobalSongService.SongReadyToPlay(song)
-		
+		maxDuration := GlobalSettings.Get(p.GuildID).Duration(core.SettingMaxSongDuration)
+		s, err := GlobalSongService.SongReadyToPlay(song, maxDuration)
 		if err != nil {
 			p.Logger.Error("error downloading the song", "error", err)
-			p.state = Stopped
+			p.songFailed(song, err)
 			return
 		}
 		song = *s
 	}
-
-	p.Logger.Info("Playing song", "title", song.Title)
-	p.stopCurrentPlayback() // Retry joining voice channel with a small delay
-	var vc *discordgo.VoiceConnection
-	for i := 0; i < 3; i++ { // Try up to 3 times
-		vc, err = p.Session.ChannelVoiceJoin(p.GuildID, p.ChannelID, false, true)
-		if err == nil {
-			break // Success
+	song.RequesterID, song.RequesterName, song.RequestedAt = t.song.RequesterID, t.song.RequesterName, t.song.RequestedAt
+	if t.song.IsClip() {
+		// Pista virtual: mismo fichero, sólo el tramo del capítulo
+		song.Title, song.Start, song.End = t.song.Title, t.song.Start, t.song.End
+		if song.End > 0 {
+			// Los capítulos de la caché son los del fichero entero: con ellos
+			// /chapter saltaría fuera del clip y lo terminaría
+			song.Chapters = nil
 		}
-		p.Logger.Error("Join failed, retrying...", "error", err, "attempt", i+1)
-		time.Sleep(2 * time.Second) // Wait before retrying
 	}
-	if err != nil {
+
+	select {
+	case <-t.cancel:
+		return
+	default:
+	}
+
+	if err := p.ensureVoice(); err != nil {
+		// Parar como superviseVoice: pasar a la siguiente canción volvería a
+		// intentar entrar (con su backoff) y a avisar, una vez por canción
 		p.Logger.Error("Join failed", "error", err)
-		p.state = Idle
+		p.voiceFailed(err)
+		p.Stop()
 		return
 	}
-	p.vc = vc
-	p.vc.Speaking(true)
 
-	dgvoice.PlayAudioFile(p.vc, song.Path, p.stopCh)
-	// al terminar la canción
-	p.doneCh <- true
+	src, err := newPCMSource(song.Path, p.sourceOptions(song, song.Start))
+	if err != nil {
+		p.Logger.Error("error decoding the song", "error", err)
+		return
+	}
+	t.playing.Store(&song)
+	t.src.Store(src)
+
+	p.engine.Record(song)
+	p.Logger.Info("Playing song", "title", song.Title)
+	crossfade := GlobalSettings.Get(p.GuildID).Duration(core.SettingCrossfade)
+	p.mixer.Add(src, crossfade)
+
+	segs := p.loadSegments(song)
+	var segments []core.Segment
+
+	// seek reinicia la decodificación en otra posición sin cortar la mezcla
+	seek := func(to time.Duration) {
+		if song.End > 0 && to >= song.End {
+			src.Close() // fuera del clip: terminar
+			return
+		}
+		next, err := newPCMSource(song.Path, p.sourceOptions(song, to))
+		if err != nil {
+			p.Logger.Error("error seeking", "error", err, "position", to)
+			return
+		}
+		if !p.mixer.Replace(src, next) {
+			return // la canción ya terminó
+		}
+		src = next
+		t.src.Store(src)
+	}
+
+	lead := crossfade + handoffLead
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-src.Done():
+			return
+		case <-t.cancel:
+			src.Close()
+			return
+		case segments = <-segs:
+		case <-t.refilter:
+			// Nuevos filtros: retomar desde la misma posición
+			seek(src.Position())
+		case to := <-t.seek:
+			seek(to)
+		case <-ticker.C:
+			if to := skipTarget(segments, p.segmentSettings(), src.Position()); to >= 0 {
+				if end := song.Start + song.Length(); song.Length() > 0 && to >= end-time.Second {
+					p.Logger.Info("Skipping trailing segment", "title", song.Title)
+					src.Close()
+					return
+				}
+				p.Logger.Info("Skipping segment", "title", song.Title, "to", to)
+				seek(to)
+				continue
+			}
+			if left := src.Remaining(); left >= 0 && left <= lead && song.Length() > lead {
+				p.Logger.Debug("Handing off to next song", "title", song.Title)
+				return
+			}
+		}
+	}
+}
+
+// segmentSettings devuelve qué segmentos salta este guild.
+func (p *DgvoicePlayer) segmentSettings() core.SegmentSettings {
+	return GlobalSettings.Get(p.GuildID).Segments()
+}
+
+// loadSegments pide en segundo plano los segmentos a saltar de la canción,
+// si el guild tiene activado el salto. El canal recibe un único valor.
+func (p *DgvoicePlayer) loadSegments(song core.Song) <-chan []core.Segment {
+	ch := make(chan []core.Segment, 1)
+	if !p.segmentSettings().Enabled || song.URL == "" {
+		return ch
+	}
+	go func() {
+		segs, err := Segments(song.URL)
+		if err != nil {
+			p.Logger.Warn("could not load segments", "error", err, "title", song.Title)
+			return
+		}
+		ch <- segs
+	}()
+	return ch
 }
@@ -0,0 +1,28 @@
+package infra
+
+import (
+	"log/slog"
+	"testing"
+
+	"feints/internal/core"
+)
+
+// TestPauseWithoutSong comprueba que pausar sin canción no deja el player
+// en Paused, y que reanudar sin canción vuelve a Idle para seguir con la
+// cola.
+func TestPauseWithoutSong(t *testing.T) {
+	p := &DgvoicePlayer{Logger: slog.New(slog.DiscardHandler), mixer: newMixer(), state: Idle}
+	p.SetQueueStrategy(core.LookupQueueStrategy("fifo"))
+
+	p.cmdHandler(cmdPause)
+	if p.state != Idle {
+		t.Errorf("tras pausar sin canción, state = %s, want %s", p.state, Idle)
+	}
+
+	// Una canción que terminó estando en pausa deja Paused sin current
+	p.state = Paused
+	p.cmdHandler(cmdPlay)
+	if p.state != Idle {
+		t.Errorf("tras /play en pausa sin canción, state = %s, want %s", p.state, Idle)
+	}
+}
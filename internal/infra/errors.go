@@ -0,0 +1,59 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Categorías de fallo al buscar, consultar o descargar una canción. Se
+// comprueban con errors.Is; el detalle va en un *SongError.
+var (
+	ErrNotFound      = errors.New("canción no encontrada o no disponible")
+	ErrTooLong       = errors.New("canción demasiado larga")
+	ErrAgeRestricted = errors.New("canción con restricción de edad")
+	ErrGeoBlocked    = errors.New("canción bloqueada en esta región")
+	ErrRateLimited   = errors.New("demasiadas peticiones a la fuente")
+)
+
+// SongError es un fallo de una canción concreta: su categoría (uno de los
+// Err* de arriba), la URL y el error original. Limit es el máximo superado
+// en los ErrTooLong.
+type SongError struct {
+	Kind  error
+	URL   string
+	Limit time.Duration
+	Err   error
+}
+
+func (e *SongError) Error() string { return fmt.Sprintf("%s: %v", e.URL, e.Err) }
+
+func (e *SongError) Unwrap() []error { return []error{e.Kind, e.Err} }
+
+// ytdlpMarkers relaciona los mensajes de yt-dlp con su categoría. El orden
+// importa: los bloqueos por región y edad también dicen "not available".
+var ytdlpMarkers = []struct {
+	kind    error
+	markers []string
+}{
+	{ErrRateLimited, []string{"http error 429", "too many requests", "confirm you're not a bot", "confirm you’re not a bot"}},
+	{ErrGeoBlocked, []string{"not available in your country", "geo restrict", "blocked it in your country", "not made this video available in your country"}},
+	{ErrAgeRestricted, []string{"confirm your age", "age-restricted", "age restricted", "inappropriate for some users"}},
+	{ErrNotFound, []string{"video unavailable", "private video", "http error 404", "unsupported url", "does not exist", "is not available", "has been removed"}},
+}
+
+// ytdlpError envuelve un fallo de yt-dlp y, si stderr permite saber qué
+// pasó, lo clasifica en un *SongError.
+func ytdlpError(op, url string, err error, stderr string) error {
+	wrapped := fmt.Errorf("yt-dlp %s error: %w - %s", op, err, stderr)
+	lower := strings.ToLower(stderr)
+	for _, m := range ytdlpMarkers {
+		for _, marker := range m.markers {
+			if strings.Contains(lower, marker) {
+				return &SongError{Kind: m.kind, URL: url, Err: wrapped}
+			}
+		}
+	}
+	return wrapped
+}
@@ -0,0 +1,65 @@
+package infra
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestYtdlpError(t *testing.T) {
+	const url = "https://www.youtube.com/watch?v=dQw4w9WgXcQ"
+	kinds := []error{ErrNotFound, ErrTooLong, ErrAgeRestricted, ErrGeoBlocked, ErrRateLimited}
+
+	// stderr tal como lo escribe yt-dlp
+	tests := []struct {
+		name   string
+		stderr string
+		want   error // nil = sin clasificar
+	}{
+		{"unavailable", "ERROR: [youtube] dQw4w9WgXcQ: Video unavailable", ErrNotFound},
+		{"account terminated", "ERROR: [youtube] dQw4w9WgXcQ: Video unavailable. This video is no longer available because the YouTube account associated with this video has been terminated.", ErrNotFound},
+		{"removed", "ERROR: [youtube] dQw4w9WgXcQ: Video unavailable. This video has been removed by the uploader", ErrNotFound},
+		{"private", "ERROR: [youtube] dQw4w9WgXcQ: Private video. Sign in if you've been granted access to this video", ErrNotFound},
+		{"not available", "ERROR: [youtube] dQw4w9WgXcQ: This video is not available", ErrNotFound},
+		{"unsupported url", "ERROR: Unsupported URL: https://example.com/", ErrNotFound},
+		{"http 404", "ERROR: [generic] Unable to download webpage: HTTP Error 404: Not Found (caused by <HTTPError 404: Not Found>)", ErrNotFound},
+		{"geo", "ERROR: [youtube] dQw4w9WgXcQ: The uploader has not made this video available in your country", ErrGeoBlocked},
+		// también dice "is not available": gana la región
+		{"geo before not found", "ERROR: [youtube] dQw4w9WgXcQ: Video unavailable. This video is not available in your country", ErrGeoBlocked},
+		{"geo restriction", "ERROR: [Dailymotion] x7tgad0: This video is not available from your location due to geo restriction", ErrGeoBlocked},
+		{"age", "ERROR: [youtube] dQw4w9WgXcQ: Sign in to confirm your age. This video may be inappropriate for some users. Use --cookies-from-browser or --cookies for the authentication.", ErrAgeRestricted},
+		// también dice "video unavailable": gana la edad
+		{"age before not found", "ERROR: [youtube] dQw4w9WgXcQ: Video unavailable. This video is age-restricted and only available on YouTube", ErrAgeRestricted},
+		{"not a bot", "ERROR: [youtube] dQw4w9WgXcQ: Sign in to confirm you’re not a bot. Use --cookies-from-browser or --cookies for the authentication.", ErrRateLimited},
+		{"not a bot ascii", "ERROR: [youtube] dQw4w9WgXcQ: Sign in to confirm you're not a bot. This helps protect our community.", ErrRateLimited},
+		{"http 429", "ERROR: unable to download video data: HTTP Error 429: Too Many Requests", ErrRateLimited},
+		{"empty", "", nil},
+		{"unrelated", "ERROR: Postprocessing: ffprobe and ffmpeg not found. Please install or provide the path using --ffmpeg-location", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cause := &exec.ExitError{}
+			err := ytdlpError("download", url, cause, tt.stderr)
+
+			if !errors.Is(err, cause) {
+				t.Errorf("el error no envuelve la causa: %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.stderr) {
+				t.Errorf("Error() = %q, falta stderr", err.Error())
+			}
+			for _, kind := range kinds {
+				if got := errors.Is(err, kind); got != (kind == tt.want) {
+					t.Errorf("errors.Is(err, %v) = %v", kind, got)
+				}
+			}
+			var songErr *SongError
+			if got := errors.As(err, &songErr); got != (tt.want != nil) {
+				t.Fatalf("errors.As(*SongError) = %v", got)
+			}
+			if songErr != nil && songErr.URL != url {
+				t.Errorf("URL = %q, want %q", songErr.URL, url)
+			}
+		})
+	}
+}
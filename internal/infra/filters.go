@@ -0,0 +1,67 @@
+package infra
+
+import (
+	"fmt"
+	"strings"
+
+	"feints/internal/core"
+)
+
+// eqBands son las bandas (frecuencia Hz, ganancia dB) de cada preset.
+var eqBands = map[string][][2]float64{
+	"pop":        {{60, -1}, {250, 2}, {1000, 3}, {4000, 2}, {12000, -1}},
+	"rock":       {{60, 4}, {250, 2}, {1000, -1}, {4000, 3}, {12000, 4}},
+	"jazz":       {{60, 3}, {250, 1}, {1000, -1}, {4000, 2}, {12000, 3}},
+	"classical":  {{60, 0}, {250, 0}, {1000, 0}, {4000, -2}, {12000, -3}},
+	"electronic": {{60, 5}, {250, 3}, {1000, 0}, {4000, 2}, {12000, 4}},
+	"vocal":      {{60, -2}, {250, -1}, {1000, 3}, {4000, 3}, {12000, 0}},
+}
+
+// ffmpegFilters traduce una cadena de filtros a la sintaxis de -af.
+// Devuelve "" si no hay filtros que aplicar.
+func ffmpegFilters(f core.FilterChain) string {
+	if f.IsZero() {
+		return ""
+	}
+
+	// Partir siempre de 48kHz para que asetrate sea predecible
+	parts := []string{fmt.Sprintf("aresample=%d", frameRate)}
+
+	for _, band := range eqBands[strings.ToLower(f.EQ)] {
+		parts = append(parts, fmt.Sprintf("equalizer=f=%g:t=q:w=1:g=%g", band[0], band[1]))
+	}
+	if f.BassBoost > 0 {
+		parts = append(parts, fmt.Sprintf("bass=g=%d:f=110:w=0.6", f.BassBoost))
+	}
+	if pitch := f.Pitch; pitch != 0 && pitch != 1 {
+		// Cambia tono y velocidad a la vez (nightcore / vaporwave)
+		parts = append(parts,
+			fmt.Sprintf("asetrate=%d", int(float64(frameRate)*pitch)),
+			fmt.Sprintf("aresample=%d", frameRate),
+		)
+	}
+	if speed := f.Speed; speed != 0 && speed != 1 {
+		parts = append(parts, atempo(speed)...)
+	}
+	if f.Karaoke {
+		parts = append(parts, "pan=stereo|c0=c0-c1|c1=c1-c0")
+	}
+	if f.EightD {
+		parts = append(parts, "apulsator=hz=0.125")
+	}
+	return strings.Join(parts, ",")
+}
+
+// atempo encadena filtros atempo, que sólo aceptan factores entre 0.5 y 2.
+func atempo(factor float64) []string {
+	var parts []string
+	for factor > 2 {
+		parts = append(parts, "atempo=2")
+		factor /= 2
+	}
+	for factor < 0.5 {
+		parts = append(parts, "atempo=0.5")
+		factor /= 0.5
+	}
+	return append(parts, fmt.Sprintf("atempo=%.4f", factor))
+}
@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"feints/internal/core"
+)
+
+// playlistPath devuelve la ruta de una playlist guardada de un guild:
+// DataDir/playlists/<guildID>/<name>.json
+func playlistPath(guildID, name string) string {
+	return filepath.Join(DataDir, "playlists", guildID, sanitizeFilename(name)+".json")
+}
+
+// LoadPlaylist carga una playlist guardada del guild.
+func LoadPlaylist(guildID, name string) ([]core.Song, error) {
+	data, err := os.ReadFile(playlistPath(guildID, name))
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer la playlist %q: %w", name, err)
+	}
+	var songs []core.Song
+	if err := json.Unmarshal(data, &songs); err != nil {
+		return nil, fmt.Errorf("playlist %q inválida: %w", name, err)
+	}
+	return songs, nil
+}
+
+// SavePlaylist guarda (o reemplaza) una playlist del guild.
+func SavePlaylist(guildID, name string, songs []core.Song) error {
+	data, err := json.MarshalIndent(songs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando la playlist %q: %w", name, err)
+	}
+	return writeFileAtomic(playlistPath(guildID, name), data)
+}
+
+// ListPlaylists devuelve los nombres de las playlists guardadas del guild,
+// en orden alfabético.
+func ListPlaylists(guildID string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(DataDir, "playlists", guildID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("no se pudieron leer las playlists: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,54 @@
+package infra
+
+import (
+	"time"
+
+	"feints/internal/core"
+)
+
+// SavedQueue es la cola de un player guardada al apagar el bot: la canción
+// que sonaba, con Start en la posición a la que había llegado, seguida de
+// las pendientes en el orden en que iban a sonar.
+type SavedQueue struct {
+	Songs   []core.Song `json:"songs"`
+	SavedAt time.Time   `json:"saved_at"`
+}
+
+// GlobalQueues guarda las colas pendientes de restaurar, en DataDir/queues.json.
+var GlobalQueues = NewGuildStore[SavedQueue]("queues")
+
+// SaveQueue guarda lo que queda por sonar en el player del guild y devuelve
+// cuántas canciones se guardaron. Sin nada pendiente no guarda nada.
+func SaveQueue(guildID string, dp core.Player) (int, error) {
+	var songs []core.Song
+	if current, pos := dp.Current(); current != nil {
+		resume := *current
+		resume.Start = max(pos, current.Start)
+		songs = append(songs, resume)
+	}
+	for _, song := range dp.ListQueue() {
+		songs = append(songs, *song)
+	}
+	if len(songs) == 0 {
+		return 0, nil
+	}
+	return len(songs), GlobalQueues.Set(guildID, SavedQueue{Songs: songs, SavedAt: time.Now()})
+}
+
+// RestoreQueue vuelve a encolar en dp la cola guardada del guild, si la hay,
+// y la borra del disco. Una cola guardada hace más de maxAge se descarta sin
+// restaurarla (maxAge 0 = sin caducidad). Devuelve cuántas canciones se
+// restauraron.
+func RestoreQueue(guildID string, dp core.Player, maxAge time.Duration) (int, error) {
+	saved, ok := GlobalQueues.Get(guildID)
+	if !ok {
+		return 0, nil
+	}
+	if maxAge > 0 && time.Since(saved.SavedAt) > maxAge {
+		return 0, GlobalQueues.Delete(guildID)
+	}
+	for _, song := range saved.Songs {
+		dp.AddSong(song)
+	}
+	return len(saved.Songs), GlobalQueues.Delete(guildID)
+}
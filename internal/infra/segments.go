@@ -0,0 +1,101 @@
+package infra
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"feints/internal/core"
+)
+
+// segmentCache evita volver a preguntar a SponsorBlock por la misma URL.
+var segmentCache = struct {
+	sync.RWMutex
+	byURL map[string][]core.Segment
+}{byURL: make(map[string][]core.Segment)}
+
+// Segments devuelve los segmentos de SponsorBlock de una URL usando
+// yt-dlp --sponsorblock-mark, sin descargar el audio.
+func Segments(url string) ([]core.Segment, error) {
+	segmentCache.RLock()
+	segs, ok := segmentCache.byURL[url]
+	segmentCache.RUnlock()
+	if ok {
+		return segs, nil
+	}
+
+	out, stderr, err := run(
+		"--cookies", "cookies.txt",
+		"--skip-download",
+		"--sponsorblock-mark", strings.Join(core.SegmentCategories, ","),
+		"--dump-single-json",
+		url,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp sponsorblock error: %w - %s", err, stderr)
+	}
+
+	segs, err = parseSegments([]byte(out))
+	if err != nil {
+		return nil, err
+	}
+
+	segmentCache.Lock()
+	segmentCache.byURL[url] = segs
+	segmentCache.Unlock()
+	return segs, nil
+}
+
+// parseSegments lee los "sponsorblock_chapters" del JSON de yt-dlp,
+// ordenados por inicio.
+func parseSegments(data []byte) ([]core.Segment, error) {
+	var raw struct {
+		Chapters []struct {
+			StartTime float64 `json:"start_time"`
+			EndTime   float64 `json:"end_time"`
+			Category  string  `json:"category"`
+		} `json:"sponsorblock_chapters"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("json parse error: %w", err)
+	}
+
+	segs := make([]core.Segment, 0, len(raw.Chapters))
+	for _, c := range raw.Chapters {
+		if c.EndTime <= c.StartTime {
+			continue
+		}
+		segs = append(segs, core.Segment{
+			Start:    seconds(c.StartTime),
+			End:      seconds(c.EndTime),
+			Category: c.Category,
+		})
+	}
+	sort.Slice(segs, func(a, b int) bool { return segs[a].Start < segs[b].Start })
+	return segs, nil
+}
+
+// skipTarget devuelve hasta dónde saltar si pos cae dentro de un segmento
+// que hay que saltar, o -1 si no. Si el destino cae a su vez en otro
+// segmento solapado, se salta también (segs va ordenado por inicio).
+func skipTarget(segs []core.Segment, settings core.SegmentSettings, pos time.Duration) time.Duration {
+	target := time.Duration(-1)
+	for _, seg := range segs {
+		at := pos
+		if target >= 0 {
+			at = target
+		}
+		// Margen de un segundo para no saltar segmentos ya casi terminados
+		if settings.Skips(seg.Category) && at >= seg.Start && at < seg.End-time.Second {
+			target = seg.End
+		}
+	}
+	return target
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,83 @@
+package infra
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"feints/internal/core"
+)
+
+func loadSegmentsFixture(t *testing.T) []core.Segment {
+	t.Helper()
+	data, err := os.ReadFile("testdata/sponsorblock.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	segs, err := parseSegments(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return segs
+}
+
+func TestParseSegments(t *testing.T) {
+	segs := loadSegmentsFixture(t)
+	// El segmento vacío (170-170) se descarta y el resto queda por inicio
+	want := []core.Segment{
+		{Start: 0, End: 12 * time.Second, Category: "intro"},
+		{Start: 60 * time.Second, End: 90500 * time.Millisecond, Category: "sponsor"},
+		{Start: 80 * time.Second, End: 110 * time.Second, Category: "selfpromo"},
+		{Start: 130 * time.Second, End: 150 * time.Second, Category: "music_offtopic"},
+		{Start: 225 * time.Second, End: 240 * time.Second, Category: "outro"},
+	}
+	if len(segs) != len(want) {
+		t.Fatalf("parseSegments = %+v, want %+v", segs, want)
+	}
+	for idx := range want {
+		if segs[idx] != want[idx] {
+			t.Errorf("segs[%d] = %+v, want %+v", idx, segs[idx], want[idx])
+		}
+	}
+}
+
+func TestParseSegmentsInvalid(t *testing.T) {
+	if _, err := parseSegments([]byte("not json")); err == nil {
+		t.Error("parseSegments aceptó JSON inválido")
+	}
+	segs, err := parseSegments([]byte(`{"title": "sin segmentos"}`))
+	if err != nil || len(segs) != 0 {
+		t.Errorf("parseSegments sin sponsorblock_chapters = %v, %v", segs, err)
+	}
+}
+
+func TestSkipTarget(t *testing.T) {
+	segs := loadSegmentsFixture(t)
+	all := core.SegmentSettings{Enabled: true, Categories: []string{"intro", "sponsor", "selfpromo", "music_offtopic", "outro"}}
+	sponsorOnly := core.SegmentSettings{Enabled: true, Categories: []string{"sponsor"}}
+	s := func(secs float64) time.Duration { return time.Duration(secs * float64(time.Second)) }
+
+	tests := []struct {
+		name     string
+		settings core.SegmentSettings
+		pos      time.Duration
+		want     time.Duration
+	}{
+		{"al empezar, en la intro", all, 0, s(12)},
+		{"fuera de segmentos", all, s(30), -1},
+		{"último segundo de un segmento", all, s(11.5), -1},
+		{"solapados: sponsor y luego selfpromo", all, s(65), s(110)},
+		{"dentro sólo del segundo solapado", all, s(95), s(110)},
+		{"categoría filtrada: intro", sponsorOnly, s(5), -1},
+		{"categoría filtrada: sin selfpromo no se encadena", sponsorOnly, s(65), s(90.5)},
+		{"categoría filtrada: selfpromo", sponsorOnly, s(95), -1},
+		{"no musical", all, s(140), s(150)},
+		{"segmento final hasta el fin del vídeo", all, s(230), s(240)},
+		{"categorías por defecto", core.SegmentSettings{Enabled: true}, s(140), s(150)},
+	}
+	for _, tt := range tests {
+		if got := skipTarget(segs, tt.settings, tt.pos); got != tt.want {
+			t.Errorf("%s: skipTarget(%v) = %v, want %v", tt.name, tt.pos, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package infra
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"feints/config"
+	"feints/internal/core"
+	"feints/internal/i18n"
+)
+
+// SettingsStore guarda los ajustes de cada guild. Sólo se persisten los
+// valores que el guild ha cambiado; el resto sale de la configuración global.
+type SettingsStore struct {
+	store    *GuildStore[map[core.SettingKey]string]
+	defaults core.Settings
+	legacy   sync.Once
+}
+
+// GlobalSettings son los ajustes por guild del bot.
+var GlobalSettings = NewSettingsStore(DefaultSettings(config.Global))
+
+// NewSettingsStore crea un store persistido en DataDir/settings.json.
+func NewSettingsStore(defaults core.Settings) *SettingsStore {
+	return &SettingsStore{
+		store:    NewGuildStore[map[core.SettingKey]string]("settings"),
+		defaults: defaults,
+	}
+}
+
+// DefaultSettings construye los valores por defecto a partir de la config global.
+func DefaultSettings(c *config.Config) core.Settings {
+	return core.Settings{
+		core.SettingLanguage:        c.Language,
+		core.SettingMaxSongDuration: c.MaxSongDuration.String(),
+		core.SettingMaxQueueLength:  strconv.Itoa(c.MaxQueueLength),
+		core.SettingDJRole:          c.DJRole,
+		core.SettingDefaultVolume:   strconv.Itoa(c.DefaultVolume),
+		core.SettingAutoplay:        "false",
+		core.SettingAutoplaySource:  string(core.AutoplaySmart),
+		core.SettingCrossfade:       c.Crossfade.String(),
+		core.SettingSponsorBlock:    "false",
+		core.SettingVoteSkip:        "true",
+		core.SettingVoteSkipPercent: strconv.Itoa(c.VoteSkipPercent),
+		core.SettingMaxUserSongs:    strconv.Itoa(c.MaxUserSongs),
+		core.SettingMaxUserDuration: c.MaxUserDuration.String(),
+		core.SettingQueueStrategy:   c.QueueStrategy,
+		core.SettingAloneTimeout:    c.AloneTimeout.String(),
+		core.SettingIdleTimeout:     c.IdleTimeout.String(),
+	}
+}
+
+// Get devuelve los ajustes efectivos del guild.
+func (s *SettingsStore) Get(guildID string) core.Settings {
+	s.legacy.Do(s.importLegacy)
+	settings := maps.Clone(s.defaults)
+	overrides, _ := s.store.Get(guildID)
+	maps.Copy(settings, overrides)
+	return settings
+}
+
+// IsDefault indica si el guild no ha cambiado el ajuste.
+func (s *SettingsStore) IsDefault(guildID string, key core.SettingKey) bool {
+	s.legacy.Do(s.importLegacy)
+	overrides, _ := s.store.Get(guildID)
+	_, ok := overrides[key]
+	return !ok
+}
+
+// Set valida y guarda un ajuste. Devuelve el valor en su forma canónica.
+func (s *SettingsStore) Set(guildID string, key core.SettingKey, raw string) (string, error) {
+	s.legacy.Do(s.importLegacy)
+	def, ok := core.LookupSetting(string(key))
+	if !ok {
+		return "", i18n.Errorf("settings.unknown", key)
+	}
+	value, err := def.Normalize(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return value, s.store.Update(guildID, func(overrides map[core.SettingKey]string) map[core.SettingKey]string {
+		overrides = maps.Clone(overrides)
+		if overrides == nil {
+			overrides = make(map[core.SettingKey]string)
+		}
+		overrides[key] = value
+		return overrides
+	})
+}
+
+// Reset vuelve un ajuste a su valor por defecto; sin clave, todos.
+func (s *SettingsStore) Reset(guildID string, key core.SettingKey) error {
+	s.legacy.Do(s.importLegacy)
+	if key == "" {
+		return s.store.Delete(guildID)
+	}
+	return s.store.Update(guildID, func(overrides map[core.SettingKey]string) map[core.SettingKey]string {
+		overrides = maps.Clone(overrides)
+		delete(overrides, key)
+		return overrides
+	})
+}
+
+// importLegacy pasa a los ajustes los ficheros en que se guardaban el
+// autoplay (data/autoplay.json) y SponsorBlock (data/segments.json) antes
+// de que existieran. Lo que el guild ya haya cambiado con /settings manda.
+func (s *SettingsStore) importLegacy() {
+	importLegacy(s, "autoplay", func(a core.AutoplaySettings) map[core.SettingKey]string {
+		values := map[core.SettingKey]string{core.SettingAutoplay: strconv.FormatBool(a.Enabled)}
+		if a.Source != "" {
+			values[core.SettingAutoplaySource] = string(a.Source)
+		}
+		if a.Value != "" {
+			values[core.SettingAutoplayValue] = a.Value
+		}
+		return values
+	})
+	importLegacy(s, "segments", func(seg core.SegmentSettings) map[core.SettingKey]string {
+		values := map[core.SettingKey]string{core.SettingSponsorBlock: strconv.FormatBool(seg.Enabled)}
+		if len(seg.Categories) > 0 {
+			values[core.SettingSponsorBlockCat] = strings.Join(seg.Categories, ",")
+		}
+		return values
+	})
+}
+
+// importLegacy importa DataDir/<name>.json, un valor T por guild, con
+// convert. Si todo va bien el fichero se renombra a <name>.json.migrated
+// para no volver a importarlo.
+func importLegacy[T any](s *SettingsStore, name string, convert func(T) map[core.SettingKey]string) {
+	path := filepath.Join(DataDir, name+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		slog.Warn("no se pudo leer el store antiguo", "path", path, "error", err)
+		return
+	}
+	var legacy map[string]T
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		slog.Warn("store antiguo corrupto, se ignora", "path", path, "error", err)
+		return
+	}
+
+	for guildID, v := range legacy {
+		err := s.store.Update(guildID, func(overrides map[core.SettingKey]string) map[core.SettingKey]string {
+			overrides = maps.Clone(overrides)
+			if overrides == nil {
+				overrides = make(map[core.SettingKey]string)
+			}
+			for key, raw := range convert(v) {
+				if _, ok := overrides[key]; ok {
+					continue
+				}
+				def, _ := core.LookupSetting(string(key))
+				value, err := def.Normalize(raw)
+				if err != nil {
+					slog.Warn("valor antiguo inválido, se ignora", "path", path, "guildID", guildID, "key", key, "error", err)
+					continue
+				}
+				overrides[key] = value
+			}
+			return overrides
+		})
+		if err != nil {
+			slog.Error("no se pudo importar el store antiguo", "path", path, "error", err)
+			return
+		}
+	}
+	if err := os.Rename(path, path+".migrated"); err != nil {
+		slog.Warn("no se pudo renombrar el store antiguo", "path", path, "error", err)
+	}
+	slog.Info("Store antiguo importado a los ajustes", "path", path, "guilds", len(legacy))
+}
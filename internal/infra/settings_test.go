@@ -0,0 +1,96 @@
+package infra
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"feints/internal/core"
+)
+
+func TestSettingsImportLegacyStores(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(DataDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"autoplay.json": `{"g1": {"enabled": true, "source": "playlist", "value": "chill"}}`,
+		"segments.json": `{"g1": {"enabled": true, "categories": ["intro", "outro"]}, "g2": {"enabled": true}}`,
+		// g2 ya cambió sponsorblock con /settings: eso manda
+		"settings.json": `{"g2": {"sponsorblock": "false"}}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(DataDir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewSettingsStore(core.Settings{
+		core.SettingAutoplay:        "false",
+		core.SettingAutoplaySource:  string(core.AutoplaySmart),
+		core.SettingSponsorBlock:    "false",
+		core.SettingSponsorBlockCat: "sponsor",
+	})
+	g1 := s.Get("g1")
+	if got := g1.Autoplay(); !got.Enabled || got.Source != core.AutoplayPlaylist || got.Value != "chill" {
+		t.Errorf("autoplay g1 = %+v", got)
+	}
+	if got := g1.Segments(); !got.Enabled || !slices.Equal(got.Categories, []string{"intro", "outro"}) {
+		t.Errorf("segments g1 = %+v", got)
+	}
+	if got := s.Get("g2").Segments(); got.Enabled || !slices.Equal(got.Categories, []string{"sponsor"}) {
+		t.Errorf("segments g2 = %+v, want lo de settings.json y las categorías por defecto", got)
+	}
+
+	for _, name := range []string{"autoplay.json", "segments.json"} {
+		path := filepath.Join(DataDir, name)
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s sigue ahí tras importarlo", name)
+		}
+		if _, err := os.Stat(path + ".migrated"); err != nil {
+			t.Errorf("%s.migrated: %v", name, err)
+		}
+	}
+}
+
+// TestSettingsConcurrentSet comprueba que cambiar a la vez ajustes distintos
+// del mismo guild no pierde ninguno.
+func TestSettingsConcurrentSet(t *testing.T) {
+	t.Chdir(t.TempDir())
+	// Aun con una sola CPU, que las escrituras se intercalen
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	s := NewSettingsStore(core.Settings{})
+	values := map[core.SettingKey]string{
+		core.SettingVoteSkip:        "true",
+		core.SettingVoteSkipPercent: "60",
+		core.SettingMaxUserSongs:    "3",
+		core.SettingQueueStrategy:   "round_robin",
+		core.SettingAutoplay:        "true",
+		core.SettingSponsorBlock:    "true",
+		core.SettingCrossfade:       "4s",
+		core.SettingDefaultVolume:   "80",
+	}
+	for range 50 {
+		var wg sync.WaitGroup
+		for key, value := range values {
+			wg.Go(func() {
+				if _, err := s.Set("g1", key, value); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+		wg.Wait()
+		got := s.Get("g1")
+		for key, value := range values {
+			if got[key] != value {
+				t.Fatalf("%s = %q, want %q (se perdió una escritura)", key, got[key], value)
+			}
+		}
+		if err := s.Reset("g1", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -26,8 +26,10 @@ func NewSongService(c *SongCache) *SongService {
 
 var GlobalSongService = NewSongService(GlobalCache)
 
-func (s *SongService) SongReadyToPlay(song core.Song) (*core.Song, error) {
-	
+// SongReadyToPlay descarga la canción si hace falta. Las que duren más de
+// maxDuration se rechazan salvo que tengan capítulos, que llegan hasta
+// MaxAlbumDuration (ver durationLimit).
+func (s *SongService) SongReadyToPlay(song core.Song, maxDuration time.Duration) (*core.Song, error) {
 
 	// 1. Si viene con URL, obtener metadata
 	if song.URL == "" {
@@ -38,6 +40,14 @@ func (s *SongService) SongReadyToPlay(song core.Song) (*core.Song, error) {
 	if err != nil {
 		return nil, err
 	}
+	if limit := durationLimit(meta, maxDuration); meta.Duration > limit {
+		return nil, &SongError{
+			Kind:  ErrTooLong,
+			URL:   song.URL,
+			Limit: limit,
+			Err:   fmt.Errorf("la canción dura %s (máximo %s)", meta.Duration, limit),
+		}
+	}
 
 	filename := sanitizeFilename(fmt.Sprintf("%s-%s.mp3", meta.Uploader, meta.Title))
 	path := filepath.Join(SongsDir, filename)
@@ -45,10 +55,21 @@ func (s *SongService) SongReadyToPlay(song core.Song) (*core.Song, error) {
 		return nil, err
 	}
 	meta.Path = path
+	s.cache.AddSong(*meta)
 
 	return meta, nil
 }
 
+// durationLimit devuelve lo máximo que puede durar song. Los vídeos largos
+// sólo tienen sentido si se pueden navegar por capítulos, y aun así hasta
+// MaxAlbumDuration.
+func durationLimit(song *core.Song, maxDuration time.Duration) time.Duration {
+	if len(song.Chapters) > 0 {
+		return max(maxDuration, MaxAlbumDuration)
+	}
+	return maxDuration
+}
+
 // sanitize helper
 func sanitizeFilename(name string) string {
 	name = strings.ReplaceAll(name, "/", "_")
@@ -57,6 +78,11 @@ func sanitizeFilename(name string) string {
 	return name
 }
 
+// LocalSongs devuelve las canciones de la biblioteca local ya cargadas en caché.
+func (s *SongService) LocalSongs() []core.Song {
+	return s.cache.Songs()
+}
+
 // GetRandomLocalSong devuelve una canción aleatoria del directorio local de canciones.
 func (s *SongService) GetRandomLocalSong() (*core.Song, error) {
 	files, err := os.ReadDir(SongsDir)
@@ -83,14 +109,14 @@ func (s *SongService) GetRandomLocalSong() (*core.Song, error) {
 		dur = time.Duration(n) * time.Second
 	}
 	// Buscar frames TXXX (pueden contener URL u otros datos)
-		var url string
-		for _, f := range tag.GetFrames("TXXX") {
-			if tf, ok := f.(id3v2.UserDefinedTextFrame); ok {
-				if strings.Contains(strings.ToLower(tf.Description), "url") || tf.Description == "" {
-					url = tf.Value
-				}
+	var url string
+	for _, f := range tag.GetFrames("TXXX") {
+		if tf, ok := f.(id3v2.UserDefinedTextFrame); ok {
+			if strings.Contains(strings.ToLower(tf.Description), "url") || tf.Description == "" {
+				url = tf.Value
 			}
 		}
+	}
 	song := &core.Song{
 		Title:    tag.Title(),
 		Uploader: tag.Artist(),
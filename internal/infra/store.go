@@ -0,0 +1,113 @@
+package infra
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DataDir es el directorio donde se persiste el estado del bot.
+const DataDir = "data"
+
+// GuildStore guarda un valor de tipo T por guild en un fichero JSON dentro
+// de DataDir. Se carga perezosamente y se reescribe entero en cada Set.
+type GuildStore[T any] struct {
+	path   string
+	mu     sync.RWMutex
+	loaded bool
+	values map[string]T
+}
+
+// NewGuildStore crea un store persistido en DataDir/<name>.json.
+func NewGuildStore[T any](name string) *GuildStore[T] {
+	return &GuildStore[T]{
+		path:   filepath.Join(DataDir, name+".json"),
+		values: make(map[string]T),
+	}
+}
+
+// Get devuelve el valor guardado para el guild y si existía.
+func (g *GuildStore[T]) Get(guildID string) (T, bool) {
+	g.load()
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	v, ok := g.values[guildID]
+	return v, ok
+}
+
+// Set guarda el valor del guild y lo persiste en disco.
+func (g *GuildStore[T]) Set(guildID string, v T) error {
+	g.load()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[guildID] = v
+	return g.save()
+}
+
+// Update cambia el valor del guild con fn y lo persiste en disco. fn recibe
+// el valor actual (el cero si no había) y corre con el store bloqueado, así
+// que dos cambios a la vez no se pisan.
+func (g *GuildStore[T]) Update(guildID string, fn func(v T) T) error {
+	g.load()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[guildID] = fn(g.values[guildID])
+	return g.save()
+}
+
+// Delete elimina el valor del guild y lo persiste en disco.
+func (g *GuildStore[T]) Delete(guildID string) error {
+	g.load()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.values, guildID)
+	return g.save()
+}
+
+func (g *GuildStore[T]) load() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.loaded {
+		return
+	}
+	g.loaded = true
+
+	data, err := os.ReadFile(g.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		slog.Warn("no se pudo leer el store", "path", g.path, "error", err)
+		return
+	}
+	if err := json.Unmarshal(data, &g.values); err != nil {
+		slog.Warn("store corrupto, se ignora", "path", g.path, "error", err)
+	}
+}
+
+// save escribe el fichero de forma atómica; requiere g.mu tomado.
+func (g *GuildStore[T]) save() error {
+	data, err := json.MarshalIndent(g.values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializando %s: %w", g.path, err)
+	}
+	return writeFileAtomic(g.path, data)
+}
+
+// writeFileAtomic escribe en un temporal y lo renombra para no dejar
+// ficheros a medias si el proceso muere durante la escritura.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creando %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("error escribiendo %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
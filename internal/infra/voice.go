@@ -0,0 +1,153 @@
+package infra
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// voiceJoinAttempts es cuántas veces se intenta entrar al canal antes de
+	// rendirse.
+	voiceJoinAttempts = 6
+	// voiceBackoffBase y voiceBackoffMax acotan la espera entre intentos, que
+	// se duplica con cada fallo.
+	voiceBackoffBase = 500 * time.Millisecond
+	voiceBackoffMax  = 20 * time.Second
+	// voiceCheckInterval es cada cuánto el supervisor revisa la conexión.
+	voiceCheckInterval = 2 * time.Second
+	// voiceDeadAfter es cuánto puede pasar llegando audio sin poder enviarlo
+	// antes de dar la conexión por muerta.
+	voiceDeadAfter = 5 * time.Second
+)
+
+// voiceHealth lo actualiza opusSender: cuándo llegó el último frame del
+// mixer y cuándo se entregó el último a Discord (en UnixNano).
+type voiceHealth struct {
+	lastFrame atomic.Int64
+	lastSent  atomic.Int64
+}
+
+func (h *voiceHealth) frame() { h.lastFrame.Store(time.Now().UnixNano()) }
+func (h *voiceHealth) sent()  { h.lastSent.Store(time.Now().UnixNano()) }
+
+// dead indica si el mixer está produciendo audio que lleva voiceDeadAfter
+// sin llegar a Discord: websocket caído, UDP sin respuesta o sin conexión.
+// En pausa o sin nada que sonar no llegan frames y nunca se da por muerta.
+func (h *voiceHealth) dead() bool {
+	now := time.Now()
+	producing := now.Sub(time.Unix(0, h.lastFrame.Load())) < time.Second
+	return producing && now.Sub(time.Unix(0, h.lastSent.Load())) > voiceDeadAfter
+}
+
+// backoff es la espera antes de reintentar tras el fallo número attempt
+// (desde 0): exponencial, con tope y la mitad al azar para que varios
+// guilds no reintenten a la vez.
+func backoff(attempt int) time.Duration {
+	d := min(voiceBackoffBase<<attempt, voiceBackoffMax)
+	return d/2 + rand.N(d/2)
+}
+
+// joinVoice entra en el canal reintentando con backoff. Devuelve el último
+// error si se agotan los intentos o si el player se cierra mientras espera.
+func (p *DgvoicePlayer) joinVoice(channelID string) (*discordgo.VoiceConnection, error) {
+	var err error
+	for attempt := range voiceJoinAttempts {
+		var vc *discordgo.VoiceConnection
+		vc, err = p.Session.ChannelVoiceJoin(p.GuildID, channelID, false, true)
+		if err == nil {
+			return vc, nil
+		}
+		if attempt == voiceJoinAttempts-1 {
+			break
+		}
+		wait := backoff(attempt)
+		p.Logger.Warn("Join failed, retrying", "error", err, "attempt", attempt+1, "wait", wait)
+		select {
+		case <-time.After(wait):
+		case <-p.quit:
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// superviseVoice vigila la conexión mientras suena algo. Si se cae, vuelve a
+// entrar en el canal y retoma la canción desde la última posición que se
+// oyó; si no lo consigue, para el player y avisa con OnVoiceFailure.
+func (p *DgvoicePlayer) superviseVoice() {
+	ticker := time.NewTicker(voiceCheckInterval)
+	defer ticker.Stop()
+
+	var resumeTrack *track
+	var resumeAt time.Duration
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+
+		t := p.currentTrack()
+		if !p.health.dead() {
+			if src := trackSource(t); src != nil {
+				resumeTrack, resumeAt = t, src.Position()
+			}
+			continue
+		}
+
+		p.Logger.Warn("Voice connection lost, reconnecting", "channelID", p.VoiceChannel())
+		if err := p.reconnect(); err != nil {
+			p.Logger.Error("Voice reconnect failed", "error", err)
+			p.voiceFailed(err)
+			p.Stop()
+			continue
+		}
+		p.health.sent()
+		if t != nil && t == resumeTrack {
+			p.Logger.Info("Voice recovered, resuming", "position", resumeAt)
+			p.Seek(resumeAt)
+		}
+	}
+}
+
+// reconnect cierra la conexión rota sin salir del canal (salir haría que el
+// bot se diera por desconectado) y vuelve a entrar.
+func (p *DgvoicePlayer) reconnect() error {
+	p.vcMu.Lock()
+	vc := p.vc
+	p.vc = nil
+	p.vcMu.Unlock()
+	if vc != nil {
+		vc.Close()
+	}
+	return p.ensureVoice()
+}
+
+// voiceFailed avisa de que no se pudo recuperar la conexión de voz.
+func (p *DgvoicePlayer) voiceFailed(err error) {
+	p.vcMu.Lock()
+	fn := p.onFailure
+	p.vcMu.Unlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// OnVoiceFailure registra fn para cuando la conexión de voz no se pueda
+// establecer o recuperar.
+func (p *DgvoicePlayer) OnVoiceFailure(fn func(err error)) {
+	p.vcMu.Lock()
+	defer p.vcMu.Unlock()
+	p.onFailure = fn
+}
+
+// trackSource devuelve el decodificador de una canción que ya suena.
+func trackSource(t *track) *pcmSource {
+	if t == nil {
+		return nil
+	}
+	return t.src.Load()
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	neturl "net/url"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +17,9 @@ import (
 const (
 	YtDlpBin        = "yt-dlp"
 	MaxSongDuration = 15 * time.Minute
+	// MaxAlbumDuration es el límite para vídeos largos (álbumes, mixes),
+	// que sólo se reproducen si tienen capítulos.
+	MaxAlbumDuration = 3 * time.Hour
 )
 
 func run(args ...string) (string, string, error) {
@@ -37,9 +42,48 @@ func Search(query string, limit int) ([]core.Song, error) {
 		fmt.Sprintf("ytsearch%d:music %s", limit, query), // forzamos búsqueda musical
 	)
 	if err != nil {
-		return nil, fmt.Errorf("yt-dlp search error: %w - %s", err, stderr)
+		return nil, ytdlpError("search", query, err, stderr)
 	}
 
+	return parseEntries(out), nil
+}
+
+// Related devuelve canciones relacionadas con url usando el mix automático
+// de YouTube (lista "RD<id>"). La propia canción se excluye del resultado.
+func Related(url string, limit int) ([]core.Song, error) {
+	id := youtubeID(url)
+	if id == "" {
+		return nil, fmt.Errorf("no se pudo extraer el id de youtube de %s", url)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	out, stderr, err := run(
+		"--dump-json",
+		"--flat-playlist",
+		"--playlist-end", strconv.Itoa(limit+1),
+		fmt.Sprintf("https://www.youtube.com/watch?v=%s&list=RD%s", id, id),
+	)
+	if err != nil {
+		return nil, ytdlpError("related", url, err, stderr)
+	}
+
+	var results []core.Song
+	for _, s := range parseEntries(out) {
+		if youtubeID(s.URL) == id || s.Duration > MaxSongDuration {
+			continue
+		}
+		results = append(results, s)
+	}
+	return results, nil
+}
+
+// parseEntries convierte la salida de --dump-json (una entrada por línea)
+// en canciones, aplicando los filtros anti-basura y de duración. Como las
+// búsquedas se guardan para todos los guilds, sólo descarta lo que no puede
+// sonar en ninguno; el límite de cada guild lo aplica quien las muestra.
+func parseEntries(out string) []core.Song {
 	lines := bytes.Split([]byte(out), []byte("\n"))
 	var results []core.Song
 	for _, line := range lines {
@@ -49,7 +93,7 @@ func Search(query string, limit int) ([]core.Song, error) {
 
 		var raw map[string]any
 		if err := json.Unmarshal(line, &raw); err != nil {
-			slog.Error("error unmarshaling", "error",err)
+			slog.Error("error unmarshaling", "error", err)
 			continue
 		}
 
@@ -65,26 +109,48 @@ func Search(query string, limit int) ([]core.Song, error) {
 		var duration time.Duration
 		if dur, ok := raw["duration"].(float64); ok {
 			duration = time.Duration(int(dur)) * time.Second
-			if duration > MaxSongDuration {
-				continue // descartamos canciones muy largas
+			if duration > MaxAlbumDuration {
+				continue // descartamos vídeos demasiado largos
 			}
 		}
 
+		// En listas planas yt-dlp a veces sólo devuelve "url"
+		url := raw["webpage_url"]
+		if url == nil {
+			url = raw["url"]
+		}
+
 		results = append(results, core.Song{
 			Title:     title,
 			Uploader:  fmt.Sprint(raw["uploader"]),
 			Thumbnail: fmt.Sprint(raw["thumbnail"]),
-			URL:       fmt.Sprint(raw["webpage_url"]),
+			URL:       fmt.Sprint(url),
 			Duration:  duration,
 		})
 	}
-	return results, nil
+	return results
+}
+
+// youtubeID extrae el id de vídeo de una URL de YouTube, o "" si no lo es.
+func youtubeID(raw string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	switch host {
+	case "youtu.be":
+		return strings.Trim(u.Path, "/")
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		return u.Query().Get("v")
+	}
+	return ""
 }
 
 func Metadata(url string) (*core.Song, error) {
 	out, stderr, err := run("--cookies", "cookies.txt", "--dump-single-json", url)
 	if err != nil {
-		return nil, fmt.Errorf("yt-dlp metadata error: %w - %s", err, stderr)
+		return nil, ytdlpError("metadata", url, err, stderr)
 	}
 
 	var raw map[string]any
@@ -101,9 +167,34 @@ func Metadata(url string) (*core.Song, error) {
 	if dur, ok := raw["duration"].(float64); ok {
 		s.Duration = time.Duration(int(dur)) * time.Second
 	}
+	s.Chapters = parseChapters(raw["chapters"])
 	return s, nil
 }
 
+// parseChapters lee la lista "chapters" del JSON de yt-dlp.
+func parseChapters(v any) []core.Chapter {
+	list, _ := v.([]any)
+	var chapters []core.Chapter
+	for _, item := range list {
+		c, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		start, _ := c["start_time"].(float64)
+		end, _ := c["end_time"].(float64)
+		title, _ := c["title"].(string)
+		if end <= start {
+			continue
+		}
+		chapters = append(chapters, core.Chapter{
+			Title: title,
+			Start: seconds(start),
+			End:   seconds(end),
+		})
+	}
+	return chapters
+}
+
 func DownloadAudio(url, path string) error {
 	_, stderr, err := run(
 		"--cookies", "cookies.txt",
@@ -113,7 +204,7 @@ func DownloadAudio(url, path string) error {
 		url,
 	)
 	if err != nil {
-		return fmt.Errorf("yt-dlp download error: %w - %s", err, stderr)
+		return ytdlpError("download", url, err, stderr)
 	}
 	return nil
 }
@@ -0,0 +1,153 @@
+package infra
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"feints/internal/core"
+)
+
+func TestParseChapters(t *testing.T) {
+	tests := []struct {
+		name string
+		json string // el campo "chapters" de --dump-single-json
+		want []core.Chapter
+	}{
+		{name: "none", json: `null`},
+		{name: "not a list", json: `"chapters"`},
+		{
+			name: "album",
+			json: `[{"start_time": 0.0, "title": "Intro", "end_time": 95.5},
+				{"start_time": 95.5, "title": "Track 2", "end_time": 301.0}]`,
+			want: []core.Chapter{
+				{Title: "Intro", Start: 0, End: 95500 * time.Millisecond},
+				{Title: "Track 2", Start: 95500 * time.Millisecond, End: 301 * time.Second},
+			},
+		},
+		{
+			name: "skips empty and malformed",
+			json: `[{"start_time": 10, "title": "Empty", "end_time": 10},
+				{"start_time": 20, "title": "Backwards", "end_time": 15},
+				"nonsense",
+				{"start_time": 20, "title": "Ok", "end_time": 30}]`,
+			want: []core.Chapter{{Title: "Ok", Start: 20 * time.Second, End: 30 * time.Second}},
+		},
+		{
+			name: "untitled",
+			json: `[{"start_time": 0, "end_time": 60}]`,
+			want: []core.Chapter{{Start: 0, End: time.Minute}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := json.Unmarshal([]byte(tt.json), &v); err != nil {
+				t.Fatal(err)
+			}
+			if got := parseChapters(v); !slices.Equal(got, tt.want) {
+				t.Errorf("parseChapters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYoutubeID(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtube.com/watch?v=dQw4w9WgXcQ&list=RDdQw4w9WgXcQ&t=42", "dQw4w9WgXcQ"},
+		{"https://m.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://music.youtube.com/watch?v=dQw4w9WgXcQ&feature=share", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"https://youtu.be/dQw4w9WgXcQ?si=abc", "dQw4w9WgXcQ"},
+		{"https://www.youtube.com/playlist?list=PL123", ""},
+		{"https://soundcloud.com/artist/track", ""},
+		{"not a url at all", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := youtubeID(tt.url); got != tt.want {
+			t.Errorf("youtubeID(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestDurationLimit(t *testing.T) {
+	chapters := []core.Chapter{{Title: "1", End: time.Minute}}
+	tests := []struct {
+		name        string
+		song        core.Song
+		maxDuration time.Duration
+		want        time.Duration
+	}{
+		{name: "plain", song: core.Song{Duration: time.Hour}, maxDuration: 15 * time.Minute, want: 15 * time.Minute},
+		// Los capítulos amplían el límite, pero no más allá de MaxAlbumDuration
+		{name: "chapters", song: core.Song{Duration: 10 * time.Hour, Chapters: chapters}, maxDuration: 15 * time.Minute, want: MaxAlbumDuration},
+		{name: "guild limit above album", song: core.Song{Chapters: chapters}, maxDuration: 4 * time.Hour, want: 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := durationLimit(&tt.song, tt.maxDuration); got != tt.want {
+				t.Errorf("durationLimit() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestClipBounds comprueba que un capítulo se decodifica sólo entre su
+// inicio y su final, también al saltar dentro de él.
+func TestClipBounds(t *testing.T) {
+	album := core.Song{
+		Title:    "Album",
+		Path:     "songs/album.mp3",
+		Duration: 40 * time.Minute,
+		Chapters: []core.Chapter{
+			{Title: "One", Start: 0, End: 4 * time.Minute},
+			{Title: "Two", Start: 4 * time.Minute, End: 9*time.Minute + 30*time.Second},
+		},
+	}
+	second := album.Clip(album.Chapters[1])
+	tests := []struct {
+		name       string
+		song       core.Song
+		start      time.Duration
+		wantArgs   []string // -ss/-t, en ese orden
+		wantLength time.Duration
+	}{
+		{name: "whole file", song: album, start: 0, wantArgs: nil, wantLength: 40 * time.Minute},
+		{name: "first chapter", song: album.Clip(album.Chapters[0]), start: 0,
+			wantArgs: []string{"-t", "240.000"}, wantLength: 4 * time.Minute},
+		{name: "second chapter", song: second, start: second.Start,
+			wantArgs: []string{"-ss", "240.000", "-t", "330.000"}, wantLength: 9*time.Minute + 30*time.Second},
+		// Un seek dentro del clip acorta -t para no pasarse del final
+		{name: "seek inside chapter", song: second, start: 5 * time.Minute,
+			wantArgs: []string{"-ss", "300.000", "-t", "270.000"}, wantLength: 9*time.Minute + 30*time.Second},
+	}
+	p := &DgvoicePlayer{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := p.sourceOptions(tt.song, tt.start)
+			if opts.Length != tt.wantLength {
+				t.Errorf("Length = %s, want %s", opts.Length, tt.wantLength)
+			}
+			args := ffmpegArgs(tt.song.Path, opts)
+			input := slices.Index(args, "-i")
+			var bounds []string
+			for idx := 0; idx < input; idx++ {
+				if args[idx] == "-ss" || args[idx] == "-t" {
+					bounds = append(bounds, args[idx], args[idx+1])
+				}
+			}
+			if !slices.Equal(bounds, tt.wantArgs) {
+				t.Errorf("ffmpeg = %v, want %v antes de -i", args, tt.wantArgs)
+			}
+			if second.Chapters != nil {
+				t.Errorf("un clip no debe llevar los capítulos del fichero: %+v", second.Chapters)
+			}
+		})
+	}
+}